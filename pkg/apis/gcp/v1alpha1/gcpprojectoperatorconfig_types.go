@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CredentialSourceType selects how the operator authenticates to GCP.
+type CredentialSourceType string
+
+const (
+	// CredentialSourceJSONKey reads a static service account key from the
+	// gcp-project-operator secret, the historical behavior.
+	CredentialSourceJSONKey CredentialSourceType = "JSONKey"
+	// CredentialSourceComputeMetadata uses the GCE/GKE metadata server, for
+	// operators running on a GCP-hosted cluster.
+	CredentialSourceComputeMetadata CredentialSourceType = "ComputeMetadata"
+	// CredentialSourceWorkloadIdentityFederation exchanges an OIDC token
+	// (e.g. from a projected ServiceAccountToken volume) for GCP credentials,
+	// for operators running on non-GCP OpenShift clusters.
+	CredentialSourceWorkloadIdentityFederation CredentialSourceType = "WorkloadIdentityFederation"
+)
+
+// CredentialSourceSpec configures how the operator obtains GCP credentials.
+type CredentialSourceSpec struct {
+	// Type selects the credential source. When empty, the operator tries the
+	// org secret's JSON key, then GCE/GKE metadata, then workload identity
+	// federation, and uses the first that succeeds.
+	// +optional
+	Type CredentialSourceType `json:"type,omitempty"`
+	// Audience is the OIDC audience requested when Type is
+	// WorkloadIdentityFederation.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+	// ImpersonatedServiceAccountEmail, when set, is the service account
+	// impersonated after the base credential source authenticates.
+	// +optional
+	ImpersonatedServiceAccountEmail string `json:"impersonatedServiceAccountEmail,omitempty"`
+}
+
+// GCPProjectOperatorConfigSpec defines operator-wide configuration for the
+// gcp-project-operator.
+type GCPProjectOperatorConfigSpec struct {
+	// CredentialSource selects how the operator authenticates to GCP.
+	// +optional
+	CredentialSource CredentialSourceSpec `json:"credentialSource,omitempty"`
+	// RequiredServices overrides the list of GCP APIs enabled on every
+	// managed project. Defaults to gcpclient.DefaultRequiredServices when
+	// empty.
+	// +optional
+	RequiredServices []string `json:"requiredServices,omitempty"`
+	// KeyRotationPeriod overrides how long a generated service account key
+	// secret is kept before being rotated. Defaults to 30 days when unset.
+	// +optional
+	KeyRotationPeriod *metav1.Duration `json:"keyRotationPeriod,omitempty"`
+}
+
+// GCPProjectOperatorConfigStatus defines the observed state of GCPProjectOperatorConfig
+type GCPProjectOperatorConfigStatus struct {
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GCPProjectOperatorConfig is the Schema for operator-wide settings such as
+// which credential source to authenticate to GCP with.
+type GCPProjectOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GCPProjectOperatorConfigSpec   `json:"spec,omitempty"`
+	Status GCPProjectOperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GCPProjectOperatorConfigList contains a list of GCPProjectOperatorConfig
+type GCPProjectOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GCPProjectOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GCPProjectOperatorConfig{}, &GCPProjectOperatorConfigList{})
+}
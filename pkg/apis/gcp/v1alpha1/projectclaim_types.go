@@ -0,0 +1,126 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectClaimConditionType is a valid value for ProjectClaimCondition.Type.
+type ProjectClaimConditionType string
+
+const (
+	// ProjectClaimConditionReady is true once the claim is bound to a
+	// ProjectReference whose GCP project is provisioned and SecretRef holds a
+	// usable service account key.
+	ProjectClaimConditionReady ProjectClaimConditionType = "Ready"
+)
+
+// ProjectClaimCondition contains details for the current condition of a ProjectClaim.
+type ProjectClaimCondition struct {
+	// Type is the type of the condition.
+	Type ProjectClaimConditionType `json:"type"`
+	// Status is the status of the condition.
+	Status corev1.ConditionStatus `json:"status"`
+	// LastProbeTime is the last time a reconcile observed this condition.
+	// +optional
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned between statuses.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ProjectClaimPhase tracks where a ProjectClaim is in its lifecycle.
+type ProjectClaimPhase string
+
+const (
+	// ClaimStatusPending means the claim is waiting on its ProjectReference
+	// to finish provisioning a project.
+	ClaimStatusPending ProjectClaimPhase = "Pending"
+	// ClaimStatusReady means SecretRef names a Secret, in the claim's
+	// namespace, holding a usable service account key for the claimed project.
+	ClaimStatusReady ProjectClaimPhase = "Ready"
+	// ClaimStatusError means the claim failed validation or provisioning.
+	ClaimStatusError ProjectClaimPhase = "Error"
+)
+
+// ProjectClaimSpec defines the GCP project a consumer is requesting.
+type ProjectClaimSpec struct {
+	// Region is the GCP region the claimed project's resources should live in.
+	Region string `json:"region"`
+	// RequiredRoles lists the IAM roles granted, on the claimed project, to
+	// the managed service account whose key is handed back in SecretRef.
+	// +optional
+	RequiredRoles []string `json:"requiredRoles,omitempty"`
+	// BillingAccount overrides the org billing account the claimed project is
+	// linked to. Defaults to the operator's configured billing account when empty.
+	// +optional
+	BillingAccount string `json:"billingAccount,omitempty"`
+	// Labels are applied to the claimed GCP project.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// ProjectID requests a specific GCP project ID rather than having the
+	// operator allocate one, for callers (like Hive) that already generated
+	// one before the project exists. Left empty, the operator allocates one.
+	// +optional
+	ProjectID string `json:"projectID,omitempty"`
+	// XPNHostProjectID, when set, names a shared-VPC (XPN) host project that
+	// also receives IAM bindings for the claimed project's service account,
+	// and therefore needs its own cleanup pass when the claim is released.
+	// +optional
+	XPNHostProjectID string `json:"xpnHostProjectID,omitempty"`
+	// ProjectReferenceCRName, once bound, names the cluster-scoped
+	// ProjectReference backing this claim.
+	// +optional
+	ProjectReferenceCRName string `json:"projectReferenceCRName,omitempty"`
+}
+
+// ProjectClaimStatus defines the observed state of a ProjectClaim.
+type ProjectClaimStatus struct {
+	// Phase is where the claim is in its lifecycle.
+	// +optional
+	Phase ProjectClaimPhase `json:"phase,omitempty"`
+	// Conditions describe the current state of the claim.
+	// +optional
+	Conditions []ProjectClaimCondition `json:"conditions,omitempty"`
+	// SecretRef names the Secret, in the claim's own namespace, holding the
+	// claimed project's service account key once Phase is Ready.
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// ProjectReferenceCRName, once bound, names the cluster-scoped
+	// ProjectReference backing this claim.
+	// +optional
+	ProjectReferenceCRName string `json:"projectReferenceCRName,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectClaim is the Schema for requesting a GCP project without tying its
+// lifecycle to a Hive ClusterDeployment. It is bound 1:1 to a cluster-scoped
+// ProjectReference, which records the operator-owned state of the
+// provisioned project.
+type ProjectClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectClaimSpec   `json:"spec,omitempty"`
+	Status ProjectClaimStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectClaimList contains a list of ProjectClaim
+type ProjectClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectClaim{}, &ProjectClaimList{})
+}
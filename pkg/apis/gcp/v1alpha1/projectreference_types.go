@@ -0,0 +1,124 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectReferenceConditionType is a valid value for ProjectReferenceCondition.Type.
+type ProjectReferenceConditionType string
+
+const (
+	// ProjectReferenceConditionReady is true once ProjectID is provisioned,
+	// has every RequiredService enabled, and its managed service account
+	// holds a current key.
+	ProjectReferenceConditionReady ProjectReferenceConditionType = "Ready"
+)
+
+// ProjectReferenceCondition contains details for the current condition of a ProjectReference.
+type ProjectReferenceCondition struct {
+	// Type is the type of the condition.
+	Type ProjectReferenceConditionType `json:"type"`
+	// Status is the status of the condition.
+	Status corev1.ConditionStatus `json:"status"`
+	// LastProbeTime is the last time a reconcile observed this condition.
+	// +optional
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned between statuses.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ProjectReferencePhase tracks where a ProjectReference is in its lifecycle.
+type ProjectReferencePhase string
+
+const (
+	// ReferenceStatusCreating means the GCP project, its required services,
+	// or its service account are still being provisioned.
+	ReferenceStatusCreating ProjectReferencePhase = "Creating"
+	// ReferenceStatusReady means ProjectID is fully provisioned and its
+	// managed service account holds a current key.
+	ReferenceStatusReady ProjectReferencePhase = "Ready"
+	// ReferenceStatusError means provisioning failed.
+	ReferenceStatusError ProjectReferencePhase = "Error"
+)
+
+// ProjectReferenceSpec records the GCP project a ProjectReference provisions
+// and tears down on behalf of its bound ProjectClaim.
+type ProjectReferenceSpec struct {
+	// ClaimRef is a back-reference to the ProjectClaim this ProjectReference
+	// was created for. There is no ownerReference between the two: a
+	// cluster-scoped object cannot be owned by a namespaced one, so binding
+	// and teardown are both handled explicitly by the projectclaim controller,
+	// the same way PersistentVolume/PersistentVolumeClaim are bound.
+	// +optional
+	ClaimRef corev1.ObjectReference `json:"claimRef,omitempty"`
+	// ProjectID is the GCP project ID this ProjectReference provisions.
+	ProjectID string `json:"projectID"`
+	// ParentFolderID is the GCP folder ProjectID is created under.
+	ParentFolderID string `json:"parentFolderID"`
+	// Region is the GCP region the project's resources should live in.
+	Region string `json:"region"`
+	// RequiredRoles lists the IAM roles granted, on ProjectID, to the managed
+	// service account whose key is returned to the bound ProjectClaim.
+	// +optional
+	RequiredRoles []string `json:"requiredRoles,omitempty"`
+	// BillingAccount is the org billing account ProjectID is linked to.
+	// +optional
+	BillingAccount string `json:"billingAccount,omitempty"`
+	// Labels are applied to ProjectID.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// XPNHostProjectID, when set, names a shared-VPC (XPN) host project that
+	// also received IAM bindings for the managed service account, and
+	// therefore needs its own cleanup pass on teardown.
+	// +optional
+	XPNHostProjectID string `json:"xpnHostProjectID,omitempty"`
+}
+
+// ProjectReferenceStatus defines the observed state of a ProjectReference.
+type ProjectReferenceStatus struct {
+	// Phase is where the project is in its lifecycle.
+	// +optional
+	Phase ProjectReferencePhase `json:"phase,omitempty"`
+	// Conditions describe the current state of the project.
+	// +optional
+	Conditions []ProjectReferenceCondition `json:"conditions,omitempty"`
+	// LastKeyRotationTimestamp is the last time the managed service account's
+	// key was rotated.
+	// +optional
+	LastKeyRotationTimestamp *metav1.Time `json:"lastKeyRotationTimestamp,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectReference is the cluster-scoped, operator-owned record of a GCP
+// project provisioned on behalf of a ProjectClaim. It exists so non-namespaced
+// operator bookkeeping (pending-operation annotations, key rotation state)
+// never has to live on the user-facing ProjectClaim.
+type ProjectReference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectReferenceSpec   `json:"spec,omitempty"`
+	Status ProjectReferenceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectReferenceList contains a list of ProjectReference
+type ProjectReferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectReference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectReference{}, &ProjectReferenceList{})
+}
@@ -2,18 +2,20 @@ package clusterdeployment
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
 
-	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/go-logr/logr"
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/pkg/apis/gcp/v1alpha1"
 	hivev1alpha1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
-	"google.golang.org/api/cloudresourcemanager/v1"
-	"google.golang.org/api/iam/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -24,21 +26,27 @@ import (
 var log = logf.Log.WithName("controller_clusterdeployment")
 
 const (
-	// Operator config
-	operatorNamespace = "gcp-project-operator"
-	controllerName    = "clusterdeployment"
+	controllerName = "clusterdeployment"
 
 	// clusterDeploymentManagedLabel is the label on the cluster deployment which indicates whether or not a cluster is OSD
 	clusterDeploymentManagedLabel = "api.openshift.com/managed"
 	// clusterPlatformLabel is the label on a cluster deployment which indicates whether or not a cluster is on GCP platform
 	clusterPlatformLabel = "hive.openshift.io/cluster-platform"
 	clusterPlatformGCP   = "gcp"
-	orgParentFolderID    = "240634451310" // Service Delivery org subfolder
 
 	// secret information
-	gcpSecretName         = "gcp"
-	orgGcpSecretName      = "gcp-project-operator"
-	osdServiceAccountName = "osd-managed-admin"
+	gcpSecretName = "gcp"
+
+	// projectCleanupFinalizer is added to every managed ClusterDeployment so
+	// that its owned ProjectClaim (and the GCP project it provisioned) is
+	// torn down before the ClusterDeployment is allowed to be garbage
+	// collected.
+	projectCleanupFinalizer = "gcp.openshift.io/project-cleanup"
+
+	// xpnHostProjectAnnotation, when present on the ClusterDeployment, names a
+	// shared-VPC (XPN) host project that also holds IAM bindings for the
+	// service account and therefore needs its own cleanup pass on teardown.
+	xpnHostProjectAnnotation = "gcp.openshift.io/xpn-host-project"
 )
 
 var OSDRequiredRoles = []string{
@@ -103,9 +111,8 @@ func Add(mgr manager.Manager) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 	return &ReconcileClusterDeployment{
-		client:           mgr.GetClient(),
-		scheme:           mgr.GetScheme(),
-		gcpClientBuilder: gcpclient.NewClient,
+		client: mgr.GetClient(),
+		scheme: mgr.GetScheme(),
 	}
 }
 
@@ -118,12 +125,17 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource ClusterDeployment
-	err = c.Watch(&source.Kind{Type: &hivev1alpha1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
+	if err := c.Watch(&source.Kind{Type: &hivev1alpha1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return err
 	}
 
-	return nil
+	// Watch for changes to the ProjectClaim each ClusterDeployment owns, so a
+	// claim reaching Ready re-triggers the owning ClusterDeployment without
+	// polling.
+	return c.Watch(&source.Kind{Type: &gcpv1alpha1.ProjectClaim{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &hivev1alpha1.ClusterDeployment{},
+	})
 }
 
 // blank assignment to verify that ReconcileClusterDeployment implements reconcile.Reconciler
@@ -133,14 +145,16 @@ var _ reconcile.Reconciler = &ReconcileClusterDeployment{}
 type ReconcileClusterDeployment struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client           client.Client
-	scheme           *runtime.Scheme
-	gcpClientBuilder func(projectName string, authJSON []byte) (gcpclient.Client, error)
+	client client.Client
+	scheme *runtime.Scheme
 }
 
-// Reconcile reads that state of the cluster for a ClusterDeployment object and makes changes based on the state read
-// and what is in the ClusterDeployment.Spec
-// TODO(Raf) Add finalizers and clean up
+// Reconcile validates a managed GCP ClusterDeployment, ensures it owns a
+// ProjectClaim covering its project's lifecycle, and once that claim is
+// Ready, copies its Secret into the ClusterDeployment's namespace under the
+// legacy gcpSecretName. All GCP provisioning and credential rotation happens
+// on the claim via the projectclaim controller; this controller only
+// translates between the two CRs.
 func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling ClusterDeployment")
@@ -159,6 +173,13 @@ func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (recon
 		return reconcile.Result{}, err
 	}
 
+	// If the ClusterDeployment is being deleted, delete its owned
+	// ProjectClaim (which drives GCP teardown via its own finalizer) and the
+	// legacy secret before letting the finalizer go so nothing is orphaned.
+	if !cd.DeletionTimestamp.IsZero() {
+		return r.release(reqLogger, cd)
+	}
+
 	err = checkDeploymentConfigRequirements(cd)
 	switch err {
 	case nil:
@@ -175,140 +196,185 @@ func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (recon
 		return reconcile.Result{}, nil
 	}
 
-	// Check if gcpSecretName in cd.Namespace exists we are done
-	// TODO(Raf) check if secret is a valid gcp secret
-	// TODO(MJ): what if we need to update secret. We should think something better.
-	// But we need to be mindful about gcp api call ammount so we would not rate limit ourselfs out.
-	if secretExists(r.client, gcpSecretName, cd.Namespace) {
-		reqLogger.Info(fmt.Sprintf("secret: %s already exists in Namespace: %s :: Nothing to do", gcpSecretName, cd.Namespace))
-		return reconcile.Result{}, nil
+	// Only stamp the finalizer once cd is confirmed to be a managed GCP
+	// ClusterDeployment; an unmanaged/non-GCP one falls through one of the
+	// cases above and must never carry this operator's finalizer.
+	if !containsString(cd.Finalizers, projectCleanupFinalizer) {
+		cd.Finalizers = append(cd.Finalizers, projectCleanupFinalizer)
+		if err := r.client.Update(context.Background(), cd); err != nil {
+			reqLogger.Error(err, "could not add finalizer to clusterDeployment")
+			return reconcile.Result{}, err
+		}
 	}
 
-	// Get org creds from secret
-	creds, err := getGCPCredentialsFromSecret(r.client, operatorNamespace, orgGcpSecretName)
+	claim, err := r.ensureProjectClaim(reqLogger, cd)
 	if err != nil {
-		reqLogger.Error(err, "could not get org Creds from secret", "Secret Name", orgGcpSecretName, "Operator Namespace", operatorNamespace)
+		reqLogger.Error(err, "could not ensure projectClaim")
 		return reconcile.Result{}, err
 	}
 
-	// Get gcpclient with creds
-	gClient, err := r.gcpClientBuilder(cd.Spec.GCP.ProjectID, creds)
-	if err != nil {
-		reqLogger.Error(err, "could not get gcp client with secret creds", "Secret Name", orgGcpSecretName, "Operator Namespace", operatorNamespace)
-		return reconcile.Result{}, err
+	if claim.Status.Phase != gcpv1alpha1.ClaimStatusReady {
+		reqLogger.Info("waiting for projectClaim to become ready", "ProjectClaim", claim.Name, "Phase", claim.Status.Phase)
+		return reconcile.Result{}, nil
 	}
 
-	// TODO(Raf) Check that operation is complete before continuing , make sure project Name does not exits , How to handle those errors
-	_, err = gClient.CreateProject(orgParentFolderID)
-	if err != nil {
-		reqLogger.Error(err, "could create project", "Parent Folder ID", orgParentFolderID, "Requested Project Name", cd.Spec.Platform.GCP.ProjectID, "Requested Region Name", cd.Spec.GCP.Region)
+	if err := r.copySecret(reqLogger, cd, claim); err != nil {
+		reqLogger.Error(err, "could not copy projectClaim secret to clusterDeployment namespace")
 		return reconcile.Result{}, err
 	}
 
-	billingAccount, err := getBillingAccountFromSecret(r.client, operatorNamespace, orgGcpSecretName)
-	if err != nil {
-		reqLogger.Error(err, "could not get org billingAccount from secret", "Secret Name", orgGcpSecretName, "Operator Namespace", operatorNamespace)
-		return reconcile.Result{}, err
-	}
+	return reconcile.Result{}, nil
+}
 
-	// TODO(Raf) Set quotas
-	// TODO(Raf) Enable APIs
-	err = gClient.EnableCloudBillingAPI(cd.Spec.Platform.GCP.ProjectID)
-	if err != nil {
-		reqLogger.Error(err, "error enabling CloudBilling")
-		return reconcile.Result{}, err
+// ensureProjectClaim returns the ProjectClaim owned by cd, creating it (named
+// after cd) on the first reconcile.
+func (r *ReconcileClusterDeployment) ensureProjectClaim(reqLogger logr.Logger, cd *hivev1alpha1.ClusterDeployment) (*gcpv1alpha1.ProjectClaim, error) {
+	claim := &gcpv1alpha1.ProjectClaim{}
+	err := r.client.Get(context.Background(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, claim)
+	if err == nil {
+		return claim, nil
 	}
-
-	// TODO(MJ): Perm issue in the api
-	// https://groups.google.com/forum/#!topic/gce-discussion/K_x9E0VIckk
-	err = gClient.CreateCloudBillingAccount(cd.Spec.Platform.GCP.ProjectID, string(billingAccount))
-	if err != nil {
-		reqLogger.Error(err, "error creating CloudBilling")
-		return reconcile.Result{}, err
+	if !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("could not get projectClaim %s/%s: %v", cd.Namespace, cd.Name, err)
 	}
 
-	err = gClient.EnableDNSAPI(cd.Spec.Platform.GCP.ProjectID)
-	if err != nil {
-		reqLogger.Error(err, "error enabling DNS API")
-		return reconcile.Result{}, err
+	claim = &gcpv1alpha1.ProjectClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cd.Name,
+			Namespace: cd.Namespace,
+		},
+		Spec: gcpv1alpha1.ProjectClaimSpec{
+			Region:           cd.Spec.GCP.Region,
+			RequiredRoles:    OSDRequiredRoles,
+			ProjectID:        cd.Spec.GCP.ProjectID,
+			XPNHostProjectID: cd.Annotations[xpnHostProjectAnnotation],
+		},
+	}
+	if err := controllerutil.SetControllerReference(cd, claim, r.scheme); err != nil {
+		return nil, fmt.Errorf("could not set owner reference on projectClaim: %v", err)
+	}
+	if err := r.client.Create(context.Background(), claim); err != nil {
+		return nil, fmt.Errorf("could not create projectClaim %s/%s: %v", cd.Namespace, cd.Name, err)
 	}
 
-	gClient, err = r.gcpClientBuilder(cd.Spec.GCP.ProjectID, creds)
-	if err != nil {
-		reqLogger.Error(err, "could not get gcp client with secret creds", "Secret Name", orgGcpSecretName, "Operator Namespace", operatorNamespace)
-		return reconcile.Result{}, err
+	return claim, nil
+}
+
+// copySecret mirrors claim's resulting credential Secret into cd's namespace
+// under the legacy gcpSecretName, so consumers that still look for that name
+// keep working. Re-running this on every reconcile after Ready is what fixes
+// rotation: a key rotated on the claim is picked up here on the next
+// reconcile instead of requiring an update path the old single-secret flow
+// never had.
+func (r *ReconcileClusterDeployment) copySecret(reqLogger logr.Logger, cd *hivev1alpha1.ClusterDeployment, claim *gcpv1alpha1.ProjectClaim) error {
+	source := &corev1.Secret{}
+	if err := r.client.Get(context.Background(), types.NamespacedName{Name: claim.Status.SecretRef, Namespace: claim.Namespace}, source); err != nil {
+		return fmt.Errorf("could not get projectClaim secret %s/%s: %v", claim.Namespace, claim.Status.SecretRef, err)
 	}
 
-	// See if GCP service account exists if not create it
-	var serviceAccount *iam.ServiceAccount
-	serviceAccount, err = gClient.GetServiceAccount(osdServiceAccountName)
-	if err != nil {
-		// Create OSDManged Service account
-		account, err := gClient.CreateServiceAccount(osdServiceAccountName, osdServiceAccountName)
-		if err != nil {
-			reqLogger.Error(err, "could create service account", "Service Account Name", osdServiceAccountName)
-			return reconcile.Result{}, err
+	dest := &corev1.Secret{}
+	err := r.client.Get(context.Background(), types.NamespacedName{Name: gcpSecretName, Namespace: cd.Namespace}, dest)
+	if kerrors.IsNotFound(err) {
+		dest = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: gcpSecretName, Namespace: cd.Namespace},
+			Data:       source.Data,
 		}
-		serviceAccount = account
+		return r.client.Create(context.Background(), dest)
 	}
-
-	// Configure policy
-	// Get policy from project
-	policy, err := gClient.GetIamPolicy(cd.Spec.GCP.ProjectID)
 	if err != nil {
-		reqLogger.Error(err, "could not get policy from project", "Project Name", cd.Spec.GCP.ProjectID)
-		return reconcile.Result{}, err
+		return fmt.Errorf("could not get secret %s/%s: %v", cd.Namespace, gcpSecretName, err)
 	}
 
-	// TODO(MJ): TESTS TESTS TESTS!!!!!!
-	newBindings, modified := addOrUpdateBinding(policy.Bindings, OSDRequiredRoles, serviceAccount.Email)
+	dest.Data = source.Data
+	return r.client.Update(context.Background(), dest)
+}
 
-	// If existing bindings have been modified update the policy
-	if modified {
-		// update policy
-		policy.Bindings = newBindings
+// release deletes cd's owned ProjectClaim (whose own finalizer drives GCP
+// teardown) and the legacy gcpSecretName secret, then removes
+// projectCleanupFinalizer once the claim is gone.
+func (r *ReconcileClusterDeployment) release(reqLogger logr.Logger, cd *hivev1alpha1.ClusterDeployment) (reconcile.Result, error) {
+	if !containsString(cd.Finalizers, projectCleanupFinalizer) {
+		return reconcile.Result{}, nil
+	}
 
-		setIamPolicyRequest := &cloudresourcemanager.SetIamPolicyRequest{
-			Policy: policy,
+	claim := &gcpv1alpha1.ProjectClaim{}
+	err := r.client.Get(context.Background(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, claim)
+	switch {
+	case kerrors.IsNotFound(err):
+		// Claim is gone, teardown is complete.
+	case err != nil:
+		return reconcile.Result{}, fmt.Errorf("could not get projectClaim %s/%s: %v", cd.Namespace, cd.Name, err)
+	case !claim.DeletionTimestamp.IsZero():
+		// Already deleting; wait for its finalizer to finish.
+		return reconcile.Result{}, nil
+	default:
+		if err := r.client.Delete(context.Background(), claim); err != nil && !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("could not delete projectClaim %s/%s: %v", cd.Namespace, cd.Name, err)
 		}
+		return reconcile.Result{}, nil
+	}
 
-		//TODO(Raf) Set Etag in policy to version policies so we get the latest always
-		_, err = gClient.SetIamPolicy(setIamPolicyRequest)
-		if err != nil {
-			reqLogger.Error(err, "could not update policy on project", "Project Name", cd.Spec.GCP.ProjectID)
-			return reconcile.Result{}, err
+	secret := &corev1.Secret{}
+	err = r.client.Get(context.Background(), types.NamespacedName{Name: gcpSecretName, Namespace: cd.Namespace}, secret)
+	if err == nil {
+		if err := r.client.Delete(context.Background(), secret); err != nil && !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("could not delete secret %s/%s: %v", cd.Namespace, gcpSecretName, err)
 		}
+	} else if !kerrors.IsNotFound(err) {
+		return reconcile.Result{}, fmt.Errorf("could not get secret %s/%s: %v", cd.Namespace, gcpSecretName, err)
 	}
 
-	// Delete service account keys if any exist
-	err = gClient.DeleteServiceAccountKeys(serviceAccount.Email)
-	if err != nil {
-		reqLogger.Error(err, "could delete service account key", "Service Account Name", serviceAccount.Email)
+	cd.Finalizers = removeString(cd.Finalizers, projectCleanupFinalizer)
+	if err := r.client.Update(context.Background(), cd); err != nil {
+		reqLogger.Error(err, "could not remove finalizer from clusterDeployment")
 		return reconcile.Result{}, err
 	}
+	return reconcile.Result{}, nil
+}
 
-	key, err := gClient.CreateServiceAccountKey(serviceAccount.Email)
-	if err != nil {
-		reqLogger.Error(err, "could create service account key", "Service Account Name", serviceAccount.Email)
-		return reconcile.Result{}, err
+// checkDeploymentConfigRequirements validates that cd is a managed GCP
+// ClusterDeployment this operator should act on, and that it carries enough
+// information (region, project ID) to provision a project for.
+func checkDeploymentConfigRequirements(cd *hivev1alpha1.ClusterDeployment) error {
+	if cd.Labels[clusterDeploymentManagedLabel] != "true" {
+		return ErrNotManagedCluster
 	}
-
-	// Create secret for the key and store it
-	privateKeyString, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
-	if err != nil {
-		reqLogger.Error(err, "could not decode secret")
-		return reconcile.Result{}, err
+	if cd.Labels[clusterPlatformLabel] != clusterPlatformGCP || cd.Spec.Platform.GCP == nil {
+		return ErrNotGCPCluster
 	}
-
-	secret := newGCPSecretCR(cd.Namespace, string(privateKeyString))
-
-	createErr := r.client.Create(context.TODO(), secret)
-	if createErr != nil {
-		reqLogger.Error(createErr, "could not create service account cred secret ", "Service Account Secret Name", gcpSecretName)
-		return reconcile.Result{}, createErr
+	if cd.Spec.Installed {
+		return ErrClusterInstalled
 	}
+	if cd.Spec.GCP.ProjectID == "" {
+		return ErrMissingProjectID
+	}
+	if cd.Spec.GCP.Region == "" {
+		return ErrMissingRegion
+	}
+	if !supportedRegions[cd.Spec.GCP.Region] {
+		return ErrRegionNotSupported
+	}
+	return nil
+}
 
-	return reconcile.Result{}, nil
+// containsString returns true if slice contains s.
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
 }
 
-// TODO(Raf) Clean serviceAccount from member in bindings
+// removeString returns a copy of slice with all occurrences of s removed.
+func removeString(slice []string, s string) []string {
+	result := make([]string, 0, len(slice))
+	for _, item := range slice {
+		if item == s {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,555 @@
+package projectclaim
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/pkg/apis/gcp/v1alpha1"
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/openshift/gcp-project-operator/pkg/operations"
+	"github.com/openshift/gcp-project-operator/pkg/project"
+	"golang.org/x/oauth2/google"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_projectclaim")
+
+const (
+	controllerName = "projectclaim"
+
+	// orgParentFolderID is the GCP folder every provisioned project is
+	// created under.
+	orgParentFolderID = "240634451310" // Service Delivery org subfolder
+
+	// defaultServiceAccountName is the managed service account created on
+	// every provisioned project, unless the claim overrides it in a future
+	// iteration.
+	// TODO: expose as a ProjectClaimSpec field once a consumer needs a
+	// different service account name than the historical OSD one.
+	defaultServiceAccountName = "osd-managed-admin"
+
+	// projectReleaseFinalizer is added to every ProjectClaim so its bound
+	// ProjectReference (and the GCP project it provisioned) is torn down
+	// before the claim is allowed to be garbage collected.
+	projectReleaseFinalizer = "gcp.openshift.io/project-release"
+
+	// keyCreatedAtAnnotation records, in RFC3339, when the active service
+	// account key held by a claim's secret was minted, on the
+	// ProjectReference backing it.
+	keyCreatedAtAnnotation = "gcp.openshift.io/key-created-at"
+	// pendingKeyRotationAnnotation stores the comma-separated resource names
+	// of service account keys a rotation superseded. They are deleted by the
+	// first reconcile that runs at least keyRotationGracePeriod after the
+	// rotation, so consumers have a chance to pick up the new key from the
+	// secret before the old one is deleted.
+	pendingKeyRotationAnnotation = "gcp.openshift.io/pending-key-rotation"
+)
+
+// defaultKeyRotationPeriod is how long a service account key secret is kept
+// before being rotated.
+var defaultKeyRotationPeriod = 30 * 24 * time.Hour
+
+// keyRotationGracePeriod is how long a rotated-out service account key is
+// kept alive once a new one has been minted, giving consumers that already
+// cached the old secret a window to pick up the new one before it is
+// deleted.
+var keyRotationGracePeriod = time.Minute
+
+// Add creates a new ProjectClaim Controller and adds it to the Manager. The
+// Manager will set fields on the Controller and Start it when the Manager is
+// Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileProjectClaim{
+		client:           mgr.GetClient(),
+		scheme:           mgr.GetScheme(),
+		gcpClientBuilder: gcpclient.NewClient,
+		recorder:         mgr.GetRecorder(controllerName),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("projectclaim-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &gcpv1alpha1.ProjectClaim{}}, &handler.EnqueueRequestForObject{})
+}
+
+// blank assignment to verify that ReconcileProjectClaim implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileProjectClaim{}
+
+// ReconcileProjectClaim reconciles a ProjectClaim object
+type ReconcileProjectClaim struct {
+	client           client.Client
+	scheme           *runtime.Scheme
+	gcpClientBuilder func(projectName string, creds *google.Credentials) (gcpclient.Client, error)
+	recorder         record.EventRecorder
+}
+
+// Reconcile binds claim to a ProjectReference, provisions (or re-verifies)
+// its GCP project through pkg/project, and materializes a Secret in the
+// claim's namespace holding the resulting service account key.
+func (r *ReconcileProjectClaim) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ProjectClaim")
+
+	claim := &gcpv1alpha1.ProjectClaim{}
+	if err := r.client.Get(context.Background(), request.NamespacedName, claim); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !claim.DeletionTimestamp.IsZero() {
+		return r.release(reqLogger, claim)
+	}
+
+	if !containsString(claim.Finalizers, projectReleaseFinalizer) {
+		claim.Finalizers = append(claim.Finalizers, projectReleaseFinalizer)
+		if err := r.client.Update(context.Background(), claim); err != nil {
+			reqLogger.Error(err, "could not add finalizer to projectClaim")
+			return reconcile.Result{}, err
+		}
+	}
+
+	if claim.Spec.Region == "" {
+		reqLogger.Info("projectClaim is missing spec.region")
+		return reconcile.Result{}, nil
+	}
+
+	reference, err := r.bindProjectReference(claim)
+	if err != nil {
+		reqLogger.Error(err, "could not bind projectClaim to a projectReference")
+		return reconcile.Result{}, err
+	}
+	if reference == nil {
+		// bindProjectReference just created the ProjectReference; let the
+		// next reconcile pick up from here.
+		return reconcile.Result{}, nil
+	}
+
+	if opName := reference.Annotations[project.PendingOperationAnnotation]; opName != "" {
+		done, result, err := r.checkPendingOperation(reqLogger, claim, reference, opName)
+		if err != nil || !done {
+			return result, err
+		}
+	}
+
+	if pending := reference.Annotations[pendingKeyRotationAnnotation]; pending != "" {
+		if err := r.completePendingKeyRotation(reqLogger, reference, pending); err != nil {
+			reqLogger.Error(err, "could not complete pending key rotation")
+			return reconcile.Result{}, err
+		}
+	}
+
+	secretName := secretRefName(claim)
+	existingSecret, err := project.GetSecret(r.client, secretName, claim.Namespace)
+	if err != nil {
+		reqLogger.Error(err, "could not get claim secret", "Secret Name", secretName, "Namespace", claim.Namespace)
+		return reconcile.Result{}, err
+	}
+
+	if existingSecret != nil {
+		due, err := r.keyRotationDue(reference)
+		if err != nil {
+			reqLogger.Error(err, "could not determine key rotation due status")
+			return reconcile.Result{}, err
+		}
+		if !due {
+			return r.markReady(reqLogger, claim, reference, secretName)
+		}
+		reqLogger.Info("service account key rotation is due", "ProjectReference", reference.Name)
+	}
+
+	creds, err := project.ResolveGCPCredentials(context.Background(), r.client)
+	if err != nil {
+		reqLogger.Error(err, "could not resolve gcp credentials")
+		return reconcile.Result{}, err
+	}
+
+	gClient, err := r.gcpClientBuilder(reference.Spec.ProjectID, creds)
+	if err != nil {
+		reqLogger.Error(err, "could not get gcp client")
+		return reconcile.Result{}, err
+	}
+
+	requiredServices, err := project.ResolveRequiredServices(context.Background(), r.client)
+	if err != nil {
+		reqLogger.Error(err, "could not resolve required services")
+		return reconcile.Result{}, err
+	}
+
+	result, pending, err := project.Ensure(gClient, project.Request{
+		ProjectID:          reference.Spec.ProjectID,
+		ParentFolderID:     reference.Spec.ParentFolderID,
+		BillingAccount:     reference.Spec.BillingAccount,
+		RequiredServices:   requiredServices,
+		RequiredRoles:      reference.Spec.RequiredRoles,
+		ServiceAccountName: defaultServiceAccountName,
+		RotatingKey:        existingSecret != nil,
+	})
+	if err != nil {
+		reqLogger.Error(err, "could not provision gcp project", "Project ID", reference.Spec.ProjectID)
+		return reconcile.Result{}, err
+	}
+
+	if pending != nil {
+		if reference.Annotations == nil {
+			reference.Annotations = map[string]string{}
+		}
+		reference.Annotations[project.PendingOperationAnnotation] = pending.Name
+		reference.Annotations[project.PendingOperationAttemptsAnnotation] = "0"
+		reference.Annotations[project.PendingOperationSourceAnnotation] = pending.Source
+		if err := r.client.Update(context.Background(), reference); err != nil {
+			reqLogger.Error(err, "could not persist pending operation", "Operation", pending.Name)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: operations.Backoff(0)}, nil
+	}
+
+	if existingSecret != nil {
+		return r.rotateSecret(reqLogger, claim, reference, existingSecret, result)
+	}
+
+	createdAt := time.Now().UTC().Format(time.RFC3339)
+
+	secret := project.NewSecretCR(secretName, claim.Namespace, result.PrivateKeyData)
+	secret.Annotations = map[string]string{keyCreatedAtAnnotation: createdAt}
+	if err := r.client.Create(context.Background(), secret); err != nil {
+		reqLogger.Error(err, "could not create claim secret", "Secret Name", secretName)
+		return reconcile.Result{}, err
+	}
+
+	// keyRotationDue reads keyCreatedAtAnnotation off reference, not the
+	// secret, so it must be recorded here too or the very next reconcile
+	// finds no annotation on reference and immediately rotates the key it
+	// just minted.
+	if reference.Annotations == nil {
+		reference.Annotations = map[string]string{}
+	}
+	reference.Annotations[keyCreatedAtAnnotation] = createdAt
+
+	return r.markReady(reqLogger, claim, reference, secretName)
+}
+
+// checkPendingOperation polls a GCP operation left over from a previous
+// reconcile. done reports whether the reconcile may proceed past this step.
+// Whether the operation succeeds or fails terminally, its bookkeeping
+// annotations are cleared so a permanently-failed operation is never
+// re-polled forever; a terminal failure is instead classified and recorded as
+// an Error condition on reference and claim.
+func (r *ReconcileProjectClaim) checkPendingOperation(reqLogger logr.Logger, claim *gcpv1alpha1.ProjectClaim, reference *gcpv1alpha1.ProjectReference, opName string) (bool, reconcile.Result, error) {
+	creds, err := project.ResolveGCPCredentials(context.Background(), r.client)
+	if err != nil {
+		reqLogger.Error(err, "could not resolve gcp credentials")
+		return false, reconcile.Result{}, err
+	}
+
+	gClient, err := r.gcpClientBuilder(reference.Spec.ProjectID, creds)
+	if err != nil {
+		reqLogger.Error(err, "could not get gcp client")
+		return false, reconcile.Result{}, err
+	}
+
+	done, opErr := project.CheckPending(gClient, project.PendingOperation{
+		Name:   opName,
+		Source: reference.Annotations[project.PendingOperationSourceAnnotation],
+	})
+
+	if !done {
+		if opErr != nil {
+			reqLogger.Error(opErr, "could not poll pending operation", "Operation", opName)
+			return false, reconcile.Result{}, opErr
+		}
+		attempt := pendingOperationAttempt(reference.Annotations[project.PendingOperationAttemptsAnnotation])
+		reference.Annotations[project.PendingOperationAttemptsAnnotation] = strconv.Itoa(attempt + 1)
+		if err := r.client.Update(context.Background(), reference); err != nil {
+			reqLogger.Error(err, "could not persist pending operation attempt count")
+			return false, reconcile.Result{}, err
+		}
+		return false, reconcile.Result{RequeueAfter: operations.Backoff(attempt)}, nil
+	}
+
+	delete(reference.Annotations, project.PendingOperationAnnotation)
+	delete(reference.Annotations, project.PendingOperationAttemptsAnnotation)
+	delete(reference.Annotations, project.PendingOperationSourceAnnotation)
+
+	if opErr != nil {
+		class := operations.Classify(opErr)
+		reqLogger.Error(opErr, "pending operation failed terminally", "Operation", opName, "Error Class", class)
+
+		now := time.Now()
+		reference.Status.Phase = gcpv1alpha1.ReferenceStatusError
+		setReferenceCondition(reference, gcpv1alpha1.ProjectReferenceConditionReady, corev1.ConditionFalse, string(class), now)
+		if err := r.client.Update(context.Background(), reference); err != nil {
+			reqLogger.Error(err, "could not record operation failure on projectReference")
+			return false, reconcile.Result{}, err
+		}
+
+		claim.Status.Phase = gcpv1alpha1.ClaimStatusError
+		setClaimCondition(claim, gcpv1alpha1.ProjectClaimConditionReady, corev1.ConditionFalse, string(class), now)
+		if err := r.client.Update(context.Background(), claim); err != nil {
+			reqLogger.Error(err, "could not record operation failure on projectClaim")
+			return false, reconcile.Result{}, err
+		}
+
+		// Bookkeeping is cleared and the failure is recorded on status; don't
+		// return opErr, or the controller would requeue and immediately
+		// re-attempt provisioning against the same terminal failure.
+		return false, reconcile.Result{}, nil
+	}
+
+	if err := r.client.Update(context.Background(), reference); err != nil {
+		reqLogger.Error(err, "could not clear pending operation annotation")
+		return false, reconcile.Result{}, err
+	}
+
+	return true, reconcile.Result{}, nil
+}
+
+// markReady marks both reference and claim Ready, pointing claim at
+// secretName. Status is only written when it actually changes: the
+// controller watches ProjectClaim with a bare EnqueueRequestForObject, so an
+// unconditional self-write here would re-enqueue this claim forever.
+func (r *ReconcileProjectClaim) markReady(reqLogger logr.Logger, claim *gcpv1alpha1.ProjectClaim, reference *gcpv1alpha1.ProjectReference, secretName string) (reconcile.Result, error) {
+	now := time.Now()
+
+	referenceChanged := reference.Status.Phase != gcpv1alpha1.ReferenceStatusReady
+	reference.Status.Phase = gcpv1alpha1.ReferenceStatusReady
+	if setReferenceCondition(reference, gcpv1alpha1.ProjectReferenceConditionReady, corev1.ConditionTrue, "Provisioned", now) {
+		referenceChanged = true
+	}
+	if referenceChanged {
+		if err := r.client.Update(context.Background(), reference); err != nil {
+			reqLogger.Error(err, "could not update projectReference status")
+			return reconcile.Result{}, err
+		}
+	}
+
+	claimChanged := claim.Status.ProjectReferenceCRName != reference.Name ||
+		claim.Status.SecretRef != secretName ||
+		claim.Status.Phase != gcpv1alpha1.ClaimStatusReady
+	claim.Status.ProjectReferenceCRName = reference.Name
+	claim.Status.SecretRef = secretName
+	claim.Status.Phase = gcpv1alpha1.ClaimStatusReady
+	if setClaimCondition(claim, gcpv1alpha1.ProjectClaimConditionReady, corev1.ConditionTrue, "Provisioned", now) {
+		claimChanged = true
+	}
+	if claimChanged {
+		if err := r.client.Update(context.Background(), claim); err != nil {
+			reqLogger.Error(err, "could not update projectClaim status")
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// bindProjectReference returns the ProjectReference bound to claim, creating
+// one (and recording its name on claim.Status) if this is the first
+// reconcile. A nil, nil return means a ProjectReference was just created and
+// the caller should stop for this cycle.
+func (r *ReconcileProjectClaim) bindProjectReference(claim *gcpv1alpha1.ProjectClaim) (*gcpv1alpha1.ProjectReference, error) {
+	name := claim.Status.ProjectReferenceCRName
+	if name == "" {
+		name = referenceNameFor(claim)
+	}
+
+	reference := &gcpv1alpha1.ProjectReference{}
+	err := r.client.Get(context.Background(), types.NamespacedName{Name: name}, reference)
+	if err == nil {
+		return reference, nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("could not get projectReference %s: %v", name, err)
+	}
+
+	projectID := claim.Spec.ProjectID
+	if projectID == "" {
+		projectID = name
+	}
+
+	billingAccount, err := project.ResolveBillingAccount(r.client, claim.Spec.BillingAccount)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve billing account: %v", err)
+	}
+
+	reference = &gcpv1alpha1.ProjectReference{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: gcpv1alpha1.ProjectReferenceSpec{
+			ClaimRef: corev1.ObjectReference{
+				Kind:      "ProjectClaim",
+				Namespace: claim.Namespace,
+				Name:      claim.Name,
+				UID:       claim.UID,
+			},
+			ProjectID:        projectID,
+			ParentFolderID:   orgParentFolderID,
+			Region:           claim.Spec.Region,
+			RequiredRoles:    claim.Spec.RequiredRoles,
+			BillingAccount:   billingAccount,
+			Labels:           claim.Spec.Labels,
+			XPNHostProjectID: claim.Spec.XPNHostProjectID,
+		},
+	}
+	if err := r.client.Create(context.Background(), reference); err != nil {
+		return nil, fmt.Errorf("could not create projectReference %s: %v", name, err)
+	}
+
+	claim.Status.ProjectReferenceCRName = name
+	claim.Status.Phase = gcpv1alpha1.ClaimStatusPending
+	if err := r.client.Update(context.Background(), claim); err != nil {
+		return nil, fmt.Errorf("could not record projectReferenceCRName on claim: %v", err)
+	}
+
+	return nil, nil
+}
+
+// release tears down the GCP project behind claim's bound ProjectReference
+// (if any), deletes the ProjectReference, and removes the claim's finalizer.
+func (r *ReconcileProjectClaim) release(reqLogger logr.Logger, claim *gcpv1alpha1.ProjectClaim) (reconcile.Result, error) {
+	if !containsString(claim.Finalizers, projectReleaseFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	if name := claim.Status.ProjectReferenceCRName; name != "" {
+		reference := &gcpv1alpha1.ProjectReference{}
+		err := r.client.Get(context.Background(), types.NamespacedName{Name: name}, reference)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("could not get projectReference %s: %v", name, err)
+		}
+
+		if err == nil {
+			if err := r.teardown(reqLogger, reference); err != nil {
+				reqLogger.Error(err, "could not tear down gcp project", "Project ID", reference.Spec.ProjectID)
+				return reconcile.Result{}, err
+			}
+
+			if err := r.client.Delete(context.Background(), reference); err != nil && !kerrors.IsNotFound(err) {
+				return reconcile.Result{}, fmt.Errorf("could not delete projectReference %s: %v", name, err)
+			}
+		}
+	}
+
+	if err := project.DeleteSecretIfExists(r.client, secretRefName(claim), claim.Namespace); err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not delete claim secret: %v", err)
+	}
+
+	claim.Finalizers = removeString(claim.Finalizers, projectReleaseFinalizer)
+	if err := r.client.Update(context.Background(), claim); err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not remove finalizer from projectClaim: %v", err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// teardown tears down the GCP project reference provisioned, logging (rather
+// than failing the release on) non-fatal errors cleaning up the service
+// account, since deleting the project reclaims it anyway.
+func (r *ReconcileProjectClaim) teardown(reqLogger logr.Logger, reference *gcpv1alpha1.ProjectReference) error {
+	creds, err := project.ResolveGCPCredentials(context.Background(), r.client)
+	if err != nil {
+		return fmt.Errorf("could not resolve gcp credentials: %v", err)
+	}
+
+	gClient, err := r.gcpClientBuilder(reference.Spec.ProjectID, creds)
+	if err != nil {
+		return fmt.Errorf("could not get gcp client: %v", err)
+	}
+
+	var hostClient gcpclient.Client
+	if reference.Spec.XPNHostProjectID != "" {
+		hostClient, err = r.gcpClientBuilder(reference.Spec.XPNHostProjectID, creds)
+		if err != nil {
+			return fmt.Errorf("could not get gcp client for xpn host project %s: %v", reference.Spec.XPNHostProjectID, err)
+		}
+	}
+
+	serviceAccountEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com", defaultServiceAccountName, reference.Spec.ProjectID)
+
+	saErrs, err := project.Teardown(gClient, hostClient, project.TeardownRequest{
+		ProjectID:           reference.Spec.ProjectID,
+		ParentFolderID:      reference.Spec.ParentFolderID,
+		ServiceAccountEmail: serviceAccountEmail,
+		XPNHostProjectID:    reference.Spec.XPNHostProjectID,
+	})
+	for _, saErr := range saErrs {
+		reqLogger.Error(saErr, "non-fatal error tearing down service account", "Service Account", serviceAccountEmail)
+	}
+
+	return err
+}
+
+// referenceNameFor derives a deterministic, cluster-scoped ProjectReference
+// name from claim, so repeat reconciles (e.g. after a crash before
+// claim.Status was persisted) bind back to the same object instead of
+// leaking an orphan.
+func referenceNameFor(claim *gcpv1alpha1.ProjectClaim) string {
+	return strings.ToLower(fmt.Sprintf("%s-%s", claim.Namespace, claim.Name))
+}
+
+// secretRefName returns the name of the Secret this claim's service account
+// key is (or will be) stored under, in the claim's own namespace.
+func secretRefName(claim *gcpv1alpha1.ProjectClaim) string {
+	if claim.Status.SecretRef != "" {
+		return claim.Status.SecretRef
+	}
+	return claim.Name
+}
+
+// containsString returns true if slice contains s.
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// removeString returns a copy of slice with all occurrences of s removed.
+func removeString(slice []string, s string) []string {
+	result := make([]string, 0, len(slice))
+	for _, item := range slice {
+		if item == s {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// pendingOperationAttempt parses the attempt counter stored in
+// project.PendingOperationAttemptsAnnotation, defaulting to 0 for a missing
+// or malformed value.
+func pendingOperationAttempt(raw string) int {
+	attempt, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0
+	}
+	return attempt
+}
@@ -0,0 +1,165 @@
+package projectclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/pkg/apis/gcp/v1alpha1"
+	"github.com/openshift/gcp-project-operator/pkg/project"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// keyRotationDue reports whether the service account key backing reference
+// is older than the configured key rotation period and should be rotated.
+func (r *ReconcileProjectClaim) keyRotationDue(reference *gcpv1alpha1.ProjectReference) (bool, error) {
+	createdAt, ok := reference.Annotations[keyCreatedAtAnnotation]
+	if !ok {
+		// No record of when the key was created (e.g. it predates this
+		// annotation); rotate now so the annotation gets populated.
+		return true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return false, fmt.Errorf("could not parse %s annotation %q: %v", keyCreatedAtAnnotation, createdAt, err)
+	}
+
+	period, err := project.ResolveKeyRotationPeriod(context.Background(), r.client, defaultKeyRotationPeriod)
+	if err != nil {
+		return false, fmt.Errorf("could not resolve key rotation period: %v", err)
+	}
+
+	return time.Since(t) >= period, nil
+}
+
+// rotateSecret overwrites claim's secret with the freshly minted key in
+// result, records the superseded keys on reference so they survive until
+// completePendingKeyRotation runs after keyRotationGracePeriod, and marks
+// claim/reference Ready.
+func (r *ReconcileProjectClaim) rotateSecret(reqLogger logr.Logger, claim *gcpv1alpha1.ProjectClaim, reference *gcpv1alpha1.ProjectReference, existingSecret *corev1.Secret, result *project.Result) (_ reconcile.Result, err error) {
+	existingSecret.StringData = map[string]string{project.SecretKey: result.PrivateKeyData}
+	if err := r.client.Update(context.Background(), existingSecret); err != nil {
+		reqLogger.Error(err, "could not update claim secret with rotated key")
+		return reconcile.Result{}, err
+	}
+
+	names := make([]string, len(result.PreviousKeys))
+	for i, key := range result.PreviousKeys {
+		names[i] = key.Name
+	}
+
+	now := time.Now()
+
+	if reference.Annotations == nil {
+		reference.Annotations = map[string]string{}
+	}
+	reference.Annotations[keyCreatedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	reference.Annotations[pendingKeyRotationAnnotation] = strings.Join(names, ",")
+	reference.Status.LastKeyRotationTimestamp = &metav1.Time{Time: now}
+	if err := r.client.Update(context.Background(), reference); err != nil {
+		reqLogger.Error(err, "could not record rotated-out service account keys")
+		return reconcile.Result{}, err
+	}
+
+	r.recorder.Eventf(claim, corev1.EventTypeNormal, "KeyRotated", "rotated GCP service account key for project %s", reference.Spec.ProjectID)
+
+	return r.markReady(reqLogger, claim, reference, existingSecret.Name)
+}
+
+// completePendingKeyRotation deletes the service account keys superseded by
+// the last rotation, once reference has had keyRotationGracePeriod to
+// propagate the new secret to consumers, then clears
+// pendingKeyRotationAnnotation.
+func (r *ReconcileProjectClaim) completePendingKeyRotation(reqLogger logr.Logger, reference *gcpv1alpha1.ProjectReference, pending string) error {
+	createdAt, err := time.Parse(time.RFC3339, reference.Annotations[keyCreatedAtAnnotation])
+	if err == nil && time.Since(createdAt) < keyRotationGracePeriod {
+		return nil
+	}
+
+	creds, err := project.ResolveGCPCredentials(context.Background(), r.client)
+	if err != nil {
+		return fmt.Errorf("could not resolve gcp credentials: %v", err)
+	}
+
+	gClient, err := r.gcpClientBuilder(reference.Spec.ProjectID, creds)
+	if err != nil {
+		return fmt.Errorf("could not get gcp client: %v", err)
+	}
+
+	for _, name := range strings.Split(pending, ",") {
+		if name == "" {
+			continue
+		}
+		if err := gClient.DeleteServiceAccountKey(name); err != nil {
+			reqLogger.Error(err, "could not delete rotated-out service account key", "Key", name)
+		}
+	}
+
+	delete(reference.Annotations, pendingKeyRotationAnnotation)
+	return r.client.Update(context.Background(), reference)
+}
+
+// setReferenceCondition upserts a condition of the given type onto
+// reference.Status.Conditions, reporting whether anything actually changed so
+// callers can skip writing status (and re-triggering their own watch) when it
+// didn't. LastProbeTime/LastTransitionTime are only touched on an actual
+// change, never bumped just because a reconcile happened to run.
+func setReferenceCondition(reference *gcpv1alpha1.ProjectReference, condType gcpv1alpha1.ProjectReferenceConditionType, status corev1.ConditionStatus, reason string, now time.Time) bool {
+	for i, cond := range reference.Status.Conditions {
+		if cond.Type != condType {
+			continue
+		}
+		if cond.Status == status && cond.Reason == reason {
+			return false
+		}
+		reference.Status.Conditions[i].Status = status
+		reference.Status.Conditions[i].Reason = reason
+		reference.Status.Conditions[i].LastProbeTime = metav1.NewTime(now)
+		reference.Status.Conditions[i].LastTransitionTime = metav1.NewTime(now)
+		return true
+	}
+
+	reference.Status.Conditions = append(reference.Status.Conditions, gcpv1alpha1.ProjectReferenceCondition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		LastProbeTime:      metav1.NewTime(now),
+		LastTransitionTime: metav1.NewTime(now),
+	})
+	return true
+}
+
+// setClaimCondition upserts a condition of the given type onto
+// claim.Status.Conditions, reporting whether anything actually changed so
+// callers can skip writing status (and re-triggering their own watch) when it
+// didn't. LastProbeTime/LastTransitionTime are only touched on an actual
+// change, never bumped just because a reconcile happened to run.
+func setClaimCondition(claim *gcpv1alpha1.ProjectClaim, condType gcpv1alpha1.ProjectClaimConditionType, status corev1.ConditionStatus, reason string, now time.Time) bool {
+	for i, cond := range claim.Status.Conditions {
+		if cond.Type != condType {
+			continue
+		}
+		if cond.Status == status && cond.Reason == reason {
+			return false
+		}
+		claim.Status.Conditions[i].Status = status
+		claim.Status.Conditions[i].Reason = reason
+		claim.Status.Conditions[i].LastProbeTime = metav1.NewTime(now)
+		claim.Status.Conditions[i].LastTransitionTime = metav1.NewTime(now)
+		return true
+	}
+
+	claim.Status.Conditions = append(claim.Status.Conditions, gcpv1alpha1.ProjectClaimCondition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		LastProbeTime:      metav1.NewTime(now),
+		LastTransitionTime: metav1.NewTime(now),
+	})
+	return true
+}
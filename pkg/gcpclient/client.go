@@ -4,10 +4,11 @@ package gcpclient
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/openshift/gcp-project-operator/pkg/operations"
 	"golang.org/x/oauth2/google"
 	cloudbilling "google.golang.org/api/cloudbilling/v1"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
@@ -16,6 +17,7 @@ import (
 	iam "google.golang.org/api/iam/v1"
 	"google.golang.org/api/option"
 	serviceManagment "google.golang.org/api/servicemanagement/v1"
+	serviceusage "google.golang.org/api/serviceusage/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
@@ -28,16 +30,46 @@ type Client interface {
 	CreateServiceAccount(name, displayName string) (*iam.ServiceAccount, error)
 	DeleteServiceAccount(accountEmail string) error
 	CreateServiceAccountKey(serviceAccountEmail string) (*iam.ServiceAccountKey, error)
+	// ListServiceAccountKeys returns every USER_MANAGED key for the service
+	// account. SYSTEM_MANAGED keys, which the API returns but refuses to
+	// delete, are excluded.
+	ListServiceAccountKeys(serviceAccountEmail string) ([]*iam.ServiceAccountKey, error)
 	DeleteServiceAccountKeys(serviceAccountEmail string) error
+	// DeleteServiceAccountKey deletes a single key by its full resource name
+	// (iam.ServiceAccountKey.Name), for rotation where only the superseded
+	// key should be removed rather than every key on the account.
+	DeleteServiceAccountKey(keyName string) error
 	// Cloudresourcemanager
-	GetIamPolicy() (*cloudresourcemanager.Policy, error)
+	GetIamPolicy(projectID string) (*cloudresourcemanager.Policy, error)
 	SetIamPolicy(setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error)
 	CreateProject(parentFolder string) (*cloudresourcemanager.Operation, error)
 	DeleteProject(parentFolder string) (*cloudresourcemanager.Empty, error)
+	// CheckCloudResourceManagerOperation polls an Operation returned by a
+	// cloudresourcemanager call (e.g. CreateProject) and reports whether it
+	// has finished, so a reconcile can resume across controller restarts
+	// instead of blocking on it.
+	CheckCloudResourceManagerOperation(name string) (done bool, err error)
+
+	// ServiceUsage
+	// EnableServices enables every service in services that is not already
+	// enabled on projectID via a single serviceusage batchEnable call, after
+	// diffing against services.list so a repeat reconcile does not re-issue
+	// Enable RPCs (and burn quota) for services that are already on.
+	EnableServices(projectID string, services []string) (*serviceusage.Operation, error)
+	// CheckServiceUsageOperation polls an Operation returned by
+	// EnableServices and reports whether it has finished.
+	CheckServiceUsageOperation(name string) (done bool, err error)
 
 	// ServiceManagement
+	//
+	// Deprecated: EnableDNSAPI and EnableCloudBillingAPI each enable a single
+	// API. They are kept as thin wrappers over EnableServices for callers
+	// that have not migrated to a declarative services list.
 	EnableDNSAPI(projectID string) error
 	EnableCloudBillingAPI(projectID string) error
+	// CheckServiceManagementOperation polls an Operation returned by a
+	// servicemanagement call and reports whether it has finished.
+	CheckServiceManagementOperation(name string) (done bool, err error)
 
 	// CloudBilling
 	CreateCloudBillingAccount(projectID, billingAccount string) error
@@ -50,6 +82,7 @@ type gcpClient struct {
 	iamClient                  *iam.Service
 	dnsClient                  *dns.Service
 	serviceManagmentClient     *serviceManagment.APIService
+	serviceUsageClient         *serviceusage.Service
 	cloudBillingClient         *cloudbilling.APIService
 
 	// Some actions requires new individual client to be
@@ -58,16 +91,13 @@ type gcpClient struct {
 	credentials *google.Credentials
 }
 
-// NewClient creates our client wrapper object for interacting with GCP.
-func NewClient(projectName string, authJSON []byte) (Client, error) {
+// NewClient creates our client wrapper object for interacting with GCP. creds
+// is resolved ahead of time by a CredentialSource (a static org key, GCE/GKE
+// metadata, or workload identity federation), so this constructor no longer
+// cares how it was obtained.
+func NewClient(projectName string, creds *google.Credentials) (Client, error) {
 	ctx := context.TODO()
 
-	// since we're using a single creds var, we should specify all the required scopes when initializing
-	creds, err := google.CredentialsFromJSON(context.TODO(), authJSON, "https://www.googleapis.com/auth/cloud-platform")
-	if err != nil {
-		return nil, fmt.Errorf("gcpclient.NewClient.google.CredentialsFromJSON %v", err)
-	}
-
 	cloudResourceManagerClient, err := cloudresourcemanager.NewService(ctx, option.WithCredentials(creds))
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.NewClient.cloudresourcemanager.NewService %v", err)
@@ -83,6 +113,11 @@ func NewClient(projectName string, authJSON []byte) (Client, error) {
 		return nil, fmt.Errorf("gcpclient.serviceManagement.NewService %v", err)
 	}
 
+	serviceUsageClient, err := serviceusage.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.serviceusage.NewService %v", err)
+	}
+
 	cloudBillingClient, err := cloudbilling.NewService(ctx, option.WithCredentials(creds))
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.cloudBillingClient.NewService %v", err)
@@ -94,6 +129,7 @@ func NewClient(projectName string, authJSON []byte) (Client, error) {
 		cloudResourceManagerClient: cloudResourceManagerClient,
 		iamClient:                  iamClient,
 		serviceManagmentClient:     serviceManagmentClient,
+		serviceUsageClient:         serviceUsageClient,
 		cloudBillingClient:         cloudBillingClient,
 		credentials:                creds,
 	}, nil
@@ -178,37 +214,50 @@ func (c *gcpClient) CreateServiceAccountKey(serviceAccountEmail string) (*iam.Se
 	return key, nil
 }
 
-//DeleteServiceAccountKeys deletes all keys associated with the service account
-func (c *gcpClient) DeleteServiceAccountKeys(serviceAccountEmail string) error {
+// ListServiceAccountKeys returns every USER_MANAGED key for the service
+// account, excluding SYSTEM_MANAGED keys which the List API returns but
+// which cannot be deleted.
+func (c *gcpClient) ListServiceAccountKeys(serviceAccountEmail string) ([]*iam.ServiceAccountKey, error) {
 	resource := fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, serviceAccountEmail)
-	response, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).Do()
+	response, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).KeyTypes("USER_MANAGED").Do()
 	if err != nil {
-		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %v", err)
-	}
-
-	if len(response.Keys) <= 1 {
-		return nil
+		return nil, fmt.Errorf("gcpclient.ListServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %v", err)
 	}
+	return response.Keys, nil
+}
 
-	for _, key := range response.Keys {
-		_, err = c.iamClient.Projects.ServiceAccounts.Keys.Delete(key.Name).Do()
+// DeleteServiceAccountKey deletes a single key by its full resource name.
+func (c *gcpClient) DeleteServiceAccountKey(keyName string) error {
+	if _, err := c.iamClient.Projects.ServiceAccounts.Keys.Delete(keyName).Do(); err != nil {
+		return fmt.Errorf("gcpclient.DeleteServiceAccountKey.Projects.ServiceAccounts.Keys.Delete: %v", err)
 	}
+	return nil
+}
 
-	// ensure only one key exits
-	newResponse, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).Do()
+// DeleteServiceAccountKeys deletes every USER_MANAGED key associated with the
+// service account, accumulating every deletion error instead of only
+// reporting the last one.
+func (c *gcpClient) DeleteServiceAccountKeys(serviceAccountEmail string) error {
+	keys, err := c.ListServiceAccountKeys(serviceAccountEmail)
 	if err != nil {
-		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %v", err)
+		return err
 	}
 
-	if len(newResponse.Keys) > 1 {
-		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys.Projects.ServiceAccounts.Keys.Delete: %v", errors.New("Could not delete all keys"))
+	var errs []string
+	for _, key := range keys {
+		if err := c.DeleteServiceAccountKey(key.Name); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys: %s", strings.Join(errs, "; "))
 	}
 
 	return nil
 }
 
-func (c *gcpClient) GetIamPolicy() (*cloudresourcemanager.Policy, error) {
-	policy, err := c.cloudResourceManagerClient.Projects.GetIamPolicy(c.projectName, &cloudresourcemanager.GetIamPolicyRequest{}).Do()
+func (c *gcpClient) GetIamPolicy(projectID string) (*cloudresourcemanager.Policy, error) {
+	policy, err := c.cloudResourceManagerClient.Projects.GetIamPolicy(projectID, &cloudresourcemanager.GetIamPolicyRequest{}).Do()
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.GetIamPolicy.Projects.ServiceAccounts.GetIamPolicy %v", err)
 	}
@@ -224,28 +273,84 @@ func (c *gcpClient) SetIamPolicy(setIamPolicyRequest *cloudresourcemanager.SetIa
 	return policy, nil
 }
 
-func (c *gcpClient) EnableCloudBillingAPI(projectID string) error {
-	enableServicerequest := &serviceManagment.EnableServiceRequest{
-		ConsumerId: fmt.Sprintf("project:%s", projectID),
-	}
-	_, err := c.serviceManagmentClient.Services.Enable("cloudbilling.googleapis.com", enableServicerequest).Do()
+// CheckCloudResourceManagerOperation polls name via the cloudresourcemanager
+// Operations.Get RPC.
+func (c *gcpClient) CheckCloudResourceManagerOperation(name string) (bool, error) {
+	waiter := operations.NewOperationWaiter(&operations.CloudResourceManagerGetter{Service: c.cloudResourceManagerClient})
+	return waiter.Waited(context.TODO(), name)
+}
+
+// CheckServiceManagementOperation polls name via the servicemanagement
+// Operations.Get RPC.
+func (c *gcpClient) CheckServiceManagementOperation(name string) (bool, error) {
+	waiter := operations.NewOperationWaiter(&operations.ServiceManagementGetter{Service: c.serviceManagmentClient})
+	return waiter.Waited(context.TODO(), name)
+}
+
+// EnableServices enables every service in services that is not already
+// enabled on projectID. It lists the project's currently enabled services
+// and diffs against services so a repeat call only re-issues Enable for
+// whatever is still missing, then enables those in a single batchEnable
+// call instead of one RPC per service.
+func (c *gcpClient) EnableServices(projectID string, services []string) (*serviceusage.Operation, error) {
+	parent := fmt.Sprintf("projects/%s", projectID)
+
+	enabled := map[string]bool{}
+	err := c.serviceUsageClient.Services.List(parent).Filter("state:ENABLED").Pages(context.TODO(), func(resp *serviceusage.ListServicesResponse) error {
+		for _, s := range resp.Services {
+			if s.Config != nil {
+				enabled[s.Config.Name] = true
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("gcpclient.EnableServices.Services.List: %v", err)
 	}
 
-	return nil
-}
+	missing := make([]string, 0, len(services))
+	for _, service := range services {
+		if !enabled[service] {
+			missing = append(missing, service)
+		}
+	}
 
-func (c *gcpClient) EnableDNSAPI(projectID string) error {
-	enableServiceRequest := &serviceManagment.EnableServiceRequest{
-		ConsumerId: fmt.Sprintf("project:%s", projectID),
+	if len(missing) == 0 {
+		return &serviceusage.Operation{Done: true}, nil
 	}
-	_, err := c.serviceManagmentClient.Services.Enable("dns.googleapis.com", enableServiceRequest).Do()
+
+	operation, err := c.serviceUsageClient.Services.BatchEnable(parent, &serviceusage.BatchEnableServicesRequest{ServiceIds: missing}).Do()
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("gcpclient.EnableServices.Services.BatchEnable: %v", err)
 	}
 
-	return nil
+	return operation, nil
+}
+
+// CheckServiceUsageOperation polls name via the serviceusage Operations.Get RPC.
+func (c *gcpClient) CheckServiceUsageOperation(name string) (bool, error) {
+	waiter := operations.NewOperationWaiter(&operations.ServiceUsageGetter{Service: c.serviceUsageClient})
+	return waiter.Waited(context.TODO(), name)
+}
+
+// EnableCloudBillingAPI enables the Cloud Billing API on projectID.
+//
+// Deprecated: use EnableServices (e.g. with DefaultRequiredServices) so
+// every required service is enabled idempotently in one batched call instead
+// of one Enable RPC per API.
+func (c *gcpClient) EnableCloudBillingAPI(projectID string) error {
+	_, err := c.EnableServices(projectID, []string{"cloudbilling.googleapis.com"})
+	return err
+}
+
+// EnableDNSAPI enables the Cloud DNS API on projectID.
+//
+// Deprecated: use EnableServices (e.g. with DefaultRequiredServices) so
+// every required service is enabled idempotently in one batched call instead
+// of one Enable RPC per API.
+func (c *gcpClient) EnableDNSAPI(projectID string) error {
+	_, err := c.EnableServices(projectID, []string{"dns.googleapis.com"})
+	return err
 }
 
 func (c *gcpClient) CreateCloudBillingAccount(projectID, billingAccountID string) error {
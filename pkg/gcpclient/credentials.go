@@ -0,0 +1,189 @@
+package gcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/compute/metadata"
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/pkg/apis/gcp/v1alpha1"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+// cloudPlatformScope is the OAuth scope required for every GCP API this
+// client talks to.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// CredentialSource resolves the google.Credentials a gcpClient should
+// authenticate with, decoupling credential acquisition from the client so
+// the operator can run with a static org-level key, GCE/GKE metadata, or
+// workload identity federation without changing any call site.
+type CredentialSource interface {
+	Credentials(ctx context.Context) (*google.Credentials, error)
+}
+
+// JSONKeySource resolves credentials from a raw service account JSON key,
+// the historical behavior of reading the gcp-project-operator org secret.
+type JSONKeySource struct {
+	AuthJSON []byte
+}
+
+// Credentials implements CredentialSource.
+func (s *JSONKeySource) Credentials(ctx context.Context) (*google.Credentials, error) {
+	creds, err := google.CredentialsFromJSON(ctx, s.AuthJSON, cloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.JSONKeySource.Credentials: %v", err)
+	}
+	return creds, nil
+}
+
+// ComputeMetadataSource resolves credentials from the GCE/GKE metadata
+// server, for an operator pod running on a GCP-hosted cluster.
+type ComputeMetadataSource struct{}
+
+// Credentials implements CredentialSource.
+func (s *ComputeMetadataSource) Credentials(ctx context.Context) (*google.Credentials, error) {
+	if !metadata.OnGCE() {
+		return nil, errors.New("gcpclient.ComputeMetadataSource.Credentials: not running on GCE/GKE")
+	}
+
+	projectID, err := metadata.ProjectIDWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.ComputeMetadataSource.Credentials: %v", err)
+	}
+
+	return &google.Credentials{
+		ProjectID:   projectID,
+		TokenSource: google.ComputeTokenSource(""),
+	}, nil
+}
+
+// WorkloadIdentityFederationSource resolves credentials from an
+// external_account JSON document that exchanges an OIDC token (e.g. one
+// mounted via a projected ServiceAccountToken volume) for GCP credentials,
+// letting the operator run on non-GCP OpenShift clusters without ever
+// storing a GCP private key.
+type WorkloadIdentityFederationSource struct {
+	// ExternalAccountJSON is the external_account credential configuration
+	// produced by `gcloud iam workload-identity-pools create-cred-config`.
+	ExternalAccountJSON []byte
+	// Audience, when set, overrides the "audience" field baked into
+	// ExternalAccountJSON, so a single mounted credential configuration can
+	// be reused against a workload identity pool/provider other than the one
+	// it was generated for.
+	Audience string
+}
+
+// Credentials implements CredentialSource.
+func (s *WorkloadIdentityFederationSource) Credentials(ctx context.Context) (*google.Credentials, error) {
+	configJSON := s.ExternalAccountJSON
+	if s.Audience != "" {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(s.ExternalAccountJSON, &raw); err != nil {
+			return nil, fmt.Errorf("gcpclient.WorkloadIdentityFederationSource.Credentials: %v", err)
+		}
+		raw["audience"] = s.Audience
+		overridden, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("gcpclient.WorkloadIdentityFederationSource.Credentials: %v", err)
+		}
+		configJSON = overridden
+	}
+
+	creds, err := google.CredentialsFromJSON(ctx, configJSON, cloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.WorkloadIdentityFederationSource.Credentials: %v", err)
+	}
+	return creds, nil
+}
+
+// ImpersonatingCredentialSource wraps a base CredentialSource and exchanges
+// its token for one belonging to TargetPrincipal, so the operator can
+// authenticate as a low-privilege base identity (e.g. a workload identity
+// federation principal) that only holds roles/iam.serviceAccountTokenCreator
+// on the service account it actually needs to act as.
+type ImpersonatingCredentialSource struct {
+	Base            CredentialSource
+	TargetPrincipal string
+}
+
+// Credentials implements CredentialSource.
+func (s *ImpersonatingCredentialSource) Credentials(ctx context.Context) (*google.Credentials, error) {
+	base, err := s.Base.Credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.ImpersonatingCredentialSource.Credentials: %v", err)
+	}
+
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: s.TargetPrincipal,
+		Scopes:          []string{cloudPlatformScope},
+	}, option.WithTokenSource(base.TokenSource))
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.ImpersonatingCredentialSource.Credentials: %v", err)
+	}
+
+	return &google.Credentials{ProjectID: base.ProjectID, TokenSource: ts}, nil
+}
+
+// FallbackCredentialSource tries each source in order and returns the first
+// one that resolves successfully, so the operator can prefer an org secret
+// where one is provisioned and fall back to metadata or federated
+// credentials where it is not.
+type FallbackCredentialSource struct {
+	Sources []CredentialSource
+}
+
+// Credentials implements CredentialSource.
+func (s *FallbackCredentialSource) Credentials(ctx context.Context) (*google.Credentials, error) {
+	var lastErr error
+	for _, source := range s.Sources {
+		creds, err := source.Credentials(ctx)
+		if err == nil {
+			return creds, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("gcpclient.FallbackCredentialSource.Credentials: no credential source succeeded, last error: %v", lastErr)
+}
+
+// ResolveCredentialSource builds the CredentialSource chain the operator
+// should use, driven by cfg (a GCPProjectOperatorConfig). A nil cfg, or one
+// with an empty CredentialSource.Type, falls back through the org secret (if
+// orgAuthJSON is non-empty), then GCE/GKE metadata, then workload identity
+// federation (if federationJSON is non-empty). Whatever source this resolves
+// to is wrapped in an ImpersonatingCredentialSource when cfg sets
+// ImpersonatedServiceAccountEmail.
+func ResolveCredentialSource(cfg *gcpv1alpha1.GCPProjectOperatorConfig, orgAuthJSON, federationJSON []byte) CredentialSource {
+	var source CredentialSource
+	if cfg != nil {
+		switch cfg.Spec.CredentialSource.Type {
+		case gcpv1alpha1.CredentialSourceJSONKey:
+			source = &JSONKeySource{AuthJSON: orgAuthJSON}
+		case gcpv1alpha1.CredentialSourceComputeMetadata:
+			source = &ComputeMetadataSource{}
+		case gcpv1alpha1.CredentialSourceWorkloadIdentityFederation:
+			source = &WorkloadIdentityFederationSource{ExternalAccountJSON: federationJSON, Audience: cfg.Spec.CredentialSource.Audience}
+		}
+	}
+
+	if source == nil {
+		var sources []CredentialSource
+		if len(orgAuthJSON) > 0 {
+			sources = append(sources, &JSONKeySource{AuthJSON: orgAuthJSON})
+		}
+		sources = append(sources, &ComputeMetadataSource{})
+		if len(federationJSON) > 0 {
+			sources = append(sources, &WorkloadIdentityFederationSource{ExternalAccountJSON: federationJSON})
+		}
+		source = &FallbackCredentialSource{Sources: sources}
+	}
+
+	if cfg != nil && cfg.Spec.CredentialSource.ImpersonatedServiceAccountEmail != "" {
+		source = &ImpersonatingCredentialSource{Base: source, TargetPrincipal: cfg.Spec.CredentialSource.ImpersonatedServiceAccountEmail}
+	}
+
+	return source
+}
@@ -0,0 +1,13 @@
+package gcpclient
+
+// DefaultRequiredServices is the set of GCP APIs a managed project needs
+// enabled. project.ResolveRequiredServices returns it when a
+// GCPProjectOperatorConfig does not override it with its own list.
+var DefaultRequiredServices = []string{
+	"compute.googleapis.com",
+	"dns.googleapis.com",
+	"cloudbilling.googleapis.com",
+	"iam.googleapis.com",
+	"cloudresourcemanager.googleapis.com",
+	"serviceusage.googleapis.com",
+}
@@ -0,0 +1,173 @@
+// Package iampolicy computes and applies etag-safe, set-based diffs between
+// the IAM policy bound to a project today and the bindings the operator
+// wants to manage, so a concurrent edit (e.g. from the GCP console) is never
+// silently clobbered and members removed from the desired state are
+// actually removed rather than left to accumulate forever.
+package iampolicy
+
+import (
+	"fmt"
+	"net/http"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// maxApplyAttempts bounds how many GET-then-SetIamPolicy attempts Apply
+// makes, the first one included, before giving up on etag conflicts.
+const maxApplyAttempts = 5
+
+// Binding is the role/members pair the operator wants to manage on a
+// project. It mirrors cloudresourcemanager.Binding but only carries the
+// fields Reconcile needs.
+type Binding struct {
+	Role    string
+	Members []string
+}
+
+// PolicyClient is the subset of gcpclient.Client Apply needs to read and
+// write a project's IAM policy. gcpclient.Client already satisfies it.
+type PolicyClient interface {
+	GetIamPolicy(projectID string) (*cloudresourcemanager.Policy, error)
+	SetIamPolicy(setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error)
+}
+
+// Reconcile computes the policy that results from applying desired on top of
+// current: every member referenced anywhere in desired is treated as
+// operator-managed, so it is added to the roles it is desired on and removed
+// from any managed role it is no longer desired on, while members the
+// operator does not manage are left untouched on every role. current.Etag is
+// carried over to updated so SetIamPolicy rejects a write that raced with a
+// concurrent edit.
+func Reconcile(current *cloudresourcemanager.Policy, desired []Binding) (updated *cloudresourcemanager.Policy, changed bool) {
+	managed := managedMembers(desired)
+
+	desiredByRole := make(map[string][]string, len(desired))
+	for _, b := range desired {
+		desiredByRole[b.Role] = b.Members
+	}
+
+	updated = &cloudresourcemanager.Policy{
+		Etag:    current.Etag,
+		Version: current.Version,
+	}
+
+	seenRoles := make(map[string]bool, len(current.Bindings))
+	for _, binding := range current.Bindings {
+		seenRoles[binding.Role] = true
+
+		desiredMembers, isManagedRole := desiredByRole[binding.Role]
+		members, roleChanged := mergeMembers(binding.Members, desiredMembers, managed, isManagedRole)
+		if roleChanged {
+			changed = true
+		}
+		if len(members) == 0 {
+			continue
+		}
+		updated.Bindings = append(updated.Bindings, &cloudresourcemanager.Binding{
+			Role:    binding.Role,
+			Members: members,
+		})
+	}
+
+	for role, members := range desiredByRole {
+		if seenRoles[role] || len(members) == 0 {
+			continue
+		}
+		updated.Bindings = append(updated.Bindings, &cloudresourcemanager.Binding{
+			Role:    role,
+			Members: append([]string(nil), members...),
+		})
+		changed = true
+	}
+
+	return updated, changed
+}
+
+// mergeMembers diffs one role's current members against its desired members.
+// Members the operator does not manage are always preserved. Managed
+// members are added when isManagedRole and present in desired, and dropped
+// otherwise.
+func mergeMembers(current, desired []string, managed map[string]bool, isManagedRole bool) ([]string, bool) {
+	changed := false
+	desiredSet := toSet(desired)
+
+	var result []string
+	for _, m := range current {
+		if managed[m] && (!isManagedRole || !desiredSet[m]) {
+			changed = true
+			continue
+		}
+		result = append(result, m)
+	}
+
+	if isManagedRole {
+		currentSet := toSet(current)
+		for _, m := range desired {
+			if !currentSet[m] {
+				result = append(result, m)
+				changed = true
+			}
+		}
+	}
+
+	return result, changed
+}
+
+func managedMembers(desired []Binding) map[string]bool {
+	managed := map[string]bool{}
+	for _, b := range desired {
+		for _, m := range b.Members {
+			managed[m] = true
+		}
+	}
+	return managed
+}
+
+func toSet(members []string) map[string]bool {
+	set := make(map[string]bool, len(members))
+	for _, m := range members {
+		set[m] = true
+	}
+	return set
+}
+
+// Apply reads projectID's current IAM policy, reconciles it against desired,
+// and writes it back if anything changed. If SetIamPolicy is rejected
+// because the etag is stale, Apply re-GETs the policy, re-applies the diff
+// against the fresh state, and retries, up to maxApplyAttempts.
+func Apply(client PolicyClient, projectID string, desired []Binding) (*cloudresourcemanager.Policy, error) {
+	var lastErr error
+
+	for attempt := 0; attempt < maxApplyAttempts; attempt++ {
+		policy, err := client.GetIamPolicy(projectID)
+		if err != nil {
+			return nil, fmt.Errorf("iampolicy.Apply.GetIamPolicy: %v", err)
+		}
+
+		updated, changed := Reconcile(policy, desired)
+		if !changed {
+			return policy, nil
+		}
+
+		result, err := client.SetIamPolicy(&cloudresourcemanager.SetIamPolicyRequest{Policy: updated})
+		if err == nil {
+			return result, nil
+		}
+
+		if !isEtagConflict(err) {
+			return nil, fmt.Errorf("iampolicy.Apply.SetIamPolicy: %v", err)
+		}
+
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("iampolicy.Apply: gave up after %d attempts due to etag conflicts: %v", maxApplyAttempts, lastErr)
+}
+
+// isEtagConflict reports whether err is the 409 GCP returns when a
+// SetIamPolicy request's Etag no longer matches the stored policy.
+func isEtagConflict(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	return ok && ae.Code == http.StatusConflict
+}
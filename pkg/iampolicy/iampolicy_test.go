@@ -0,0 +1,198 @@
+package iampolicy
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func membersOf(policy *cloudresourcemanager.Policy, role string) []string {
+	for _, b := range policy.Bindings {
+		if b.Role == role {
+			members := append([]string(nil), b.Members...)
+			sort.Strings(members)
+			return members
+		}
+	}
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	const sa = "serviceAccount:osd-managed-admin@o-123.iam.gserviceaccount.com"
+	const foreignUser = "user:someone@example.com"
+	const foreignSA = "serviceAccount:other@o-123.iam.gserviceaccount.com"
+
+	tests := []struct {
+		name        string
+		current     *cloudresourcemanager.Policy
+		desired     []Binding
+		wantChanged bool
+		wantRoles   map[string][]string
+	}{
+		{
+			name: "merge adds a missing managed member to an existing role",
+			current: &cloudresourcemanager.Policy{
+				Etag: "etag-1",
+				Bindings: []*cloudresourcemanager.Binding{
+					{Role: "roles/storage.admin", Members: []string{foreignUser}},
+				},
+			},
+			desired:     []Binding{{Role: "roles/storage.admin", Members: []string{sa}}},
+			wantChanged: true,
+			wantRoles: map[string][]string{
+				"roles/storage.admin": {foreignUser, sa},
+			},
+		},
+		{
+			name: "shrink removes a managed member no longer desired on a role",
+			current: &cloudresourcemanager.Policy{
+				Etag: "etag-1",
+				Bindings: []*cloudresourcemanager.Binding{
+					{Role: "roles/dns.admin", Members: []string{sa, foreignUser}},
+				},
+			},
+			desired:     []Binding{{Role: "roles/storage.admin", Members: []string{sa}}},
+			wantChanged: true,
+			wantRoles: map[string][]string{
+				"roles/dns.admin":     {foreignUser},
+				"roles/storage.admin": {sa},
+			},
+		},
+		{
+			name: "foreign members on a managed role are preserved",
+			current: &cloudresourcemanager.Policy{
+				Etag: "etag-1",
+				Bindings: []*cloudresourcemanager.Binding{
+					{Role: "roles/compute.admin", Members: []string{sa, foreignSA, foreignUser}},
+				},
+			},
+			desired:     []Binding{{Role: "roles/compute.admin", Members: []string{sa}}},
+			wantChanged: false,
+			wantRoles: map[string][]string{
+				"roles/compute.admin": {foreignSA, foreignUser, sa},
+			},
+		},
+		{
+			name: "already in desired state is a no-op",
+			current: &cloudresourcemanager.Policy{
+				Etag: "etag-1",
+				Bindings: []*cloudresourcemanager.Binding{
+					{Role: "roles/storage.admin", Members: []string{sa}},
+				},
+			},
+			desired:     []Binding{{Role: "roles/storage.admin", Members: []string{sa}}},
+			wantChanged: false,
+			wantRoles: map[string][]string{
+				"roles/storage.admin": {sa},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated, changed := Reconcile(test.current, test.desired)
+
+			if changed != test.wantChanged {
+				t.Errorf("Reconcile() changed = %v, want %v", changed, test.wantChanged)
+			}
+			if updated.Etag != test.current.Etag {
+				t.Errorf("Reconcile() dropped the etag: got %q, want %q", updated.Etag, test.current.Etag)
+			}
+			for role, want := range test.wantRoles {
+				got := membersOf(updated, role)
+				sort.Strings(want)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Reconcile() role %s members = %v, want %v", role, got, want)
+				}
+			}
+		})
+	}
+}
+
+type fakePolicyClient struct {
+	getCalls int
+	setCalls int
+	policies []*cloudresourcemanager.Policy // returned by successive GetIamPolicy calls; the last one repeats
+	setErrs  []error                        // returned by successive SetIamPolicy calls; the last one repeats
+}
+
+func (f *fakePolicyClient) GetIamPolicy(projectID string) (*cloudresourcemanager.Policy, error) {
+	i := f.getCalls
+	if i >= len(f.policies) {
+		i = len(f.policies) - 1
+	}
+	f.getCalls++
+	return f.policies[i], nil
+}
+
+func (f *fakePolicyClient) SetIamPolicy(req *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+	i := f.setCalls
+	if i >= len(f.setErrs) {
+		i = len(f.setErrs) - 1
+	}
+	f.setCalls++
+	err := f.setErrs[i]
+	if err != nil {
+		return nil, err
+	}
+	return req.Policy, nil
+}
+
+func TestApplyRetriesOnEtagConflict(t *testing.T) {
+	const sa = "serviceAccount:osd-managed-admin@o-123.iam.gserviceaccount.com"
+
+	staleConflict := &googleapi.Error{Code: http.StatusConflict}
+	client := &fakePolicyClient{
+		policies: []*cloudresourcemanager.Policy{
+			{Etag: "stale", Bindings: []*cloudresourcemanager.Binding{}},
+			{Etag: "fresh", Bindings: []*cloudresourcemanager.Binding{}},
+		},
+		setErrs: []error{staleConflict, nil},
+	}
+
+	result, err := Apply(client, "o-123", []Binding{{Role: "roles/storage.admin", Members: []string{sa}}})
+	if err != nil {
+		t.Fatalf("Apply() returned unexpected error: %v", err)
+	}
+	if result.Etag != "fresh" {
+		t.Errorf("Apply() did not re-GET after the etag conflict, got etag %q", result.Etag)
+	}
+	if client.getCalls != 2 || client.setCalls != 2 {
+		t.Errorf("Apply() called Get %d times and Set %d times, want 1 retry of each", client.getCalls, client.setCalls)
+	}
+}
+
+func TestApplyGivesUpAfterMaxAttempts(t *testing.T) {
+	const sa = "serviceAccount:osd-managed-admin@o-123.iam.gserviceaccount.com"
+
+	staleConflict := &googleapi.Error{Code: http.StatusConflict}
+	client := &fakePolicyClient{
+		policies: []*cloudresourcemanager.Policy{{Etag: "stale", Bindings: []*cloudresourcemanager.Binding{}}},
+		setErrs:  []error{staleConflict},
+	}
+
+	if _, err := Apply(client, "o-123", []Binding{{Role: "roles/storage.admin", Members: []string{sa}}}); err == nil {
+		t.Fatal("Apply() expected an error after exhausting retries, got nil")
+	}
+}
+
+func TestApplyPropagatesNonConflictErrors(t *testing.T) {
+	const sa = "serviceAccount:osd-managed-admin@o-123.iam.gserviceaccount.com"
+
+	permissionDenied := &googleapi.Error{Code: http.StatusForbidden}
+	client := &fakePolicyClient{
+		policies: []*cloudresourcemanager.Policy{{Etag: "stale", Bindings: []*cloudresourcemanager.Binding{}}},
+		setErrs:  []error{permissionDenied},
+	}
+
+	if _, err := Apply(client, "o-123", []Binding{{Role: "roles/storage.admin", Members: []string{sa}}}); err == nil {
+		t.Fatal("Apply() expected the permission error to propagate, got nil")
+	}
+	if client.setCalls != 1 {
+		t.Errorf("Apply() retried a non-conflict error, setCalls = %d", client.setCalls)
+	}
+}
@@ -0,0 +1,208 @@
+// Package operations provides a small waiter for GCP long-running Operations
+// so reconcilers can poll them across reconcile loops instead of blocking.
+package operations
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/googleapi"
+	servicemanagement "google.golang.org/api/servicemanagement/v1"
+	serviceusage "google.golang.org/api/serviceusage/v1"
+)
+
+// maxBackoff caps the delay OperationWaiter callers are told to requeue
+// after, before Backoff adds its up-to-20% jitter.
+const maxBackoff = 60 * time.Second
+
+// Getter abstracts the "operations.get" RPC so OperationWaiter can poll a
+// long-running operation regardless of which GCP API issued it.
+type Getter interface {
+	// Get fetches the named operation and reports whether it has finished,
+	// along with the error it failed with if it finished unsuccessfully.
+	Get(name string) (done bool, err error)
+}
+
+// CloudResourceManagerGetter adapts a cloudresourcemanager.Service to Getter,
+// for Operations returned by calls like Projects.Create.
+type CloudResourceManagerGetter struct {
+	Service *cloudresourcemanager.Service
+}
+
+// Get implements Getter.
+func (g *CloudResourceManagerGetter) Get(name string) (bool, error) {
+	op, err := g.Service.Operations.Get(name).Do()
+	if err != nil {
+		return false, fmt.Errorf("operations.CloudResourceManagerGetter.Get: %v", err)
+	}
+	if !op.Done {
+		return false, nil
+	}
+	if op.Error != nil {
+		return true, &OperationError{Name: name, Code: op.Error.Code, Message: op.Error.Message}
+	}
+	return true, nil
+}
+
+// ServiceManagementGetter adapts a servicemanagement.APIService to Getter,
+// for Operations returned by calls like Services.Enable.
+type ServiceManagementGetter struct {
+	Service *servicemanagement.APIService
+}
+
+// Get implements Getter.
+func (g *ServiceManagementGetter) Get(name string) (bool, error) {
+	op, err := g.Service.Operations.Get(name).Do()
+	if err != nil {
+		return false, fmt.Errorf("operations.ServiceManagementGetter.Get: %v", err)
+	}
+	if !op.Done {
+		return false, nil
+	}
+	if op.Error != nil {
+		return true, &OperationError{Name: name, Code: op.Error.Code, Message: op.Error.Message}
+	}
+	return true, nil
+}
+
+// ServiceUsageGetter adapts a serviceusage.Service to Getter, for Operations
+// returned by calls like Services.BatchEnable.
+type ServiceUsageGetter struct {
+	Service *serviceusage.Service
+}
+
+// Get implements Getter.
+func (g *ServiceUsageGetter) Get(name string) (bool, error) {
+	op, err := g.Service.Operations.Get(name).Do()
+	if err != nil {
+		return false, fmt.Errorf("operations.ServiceUsageGetter.Get: %v", err)
+	}
+	if !op.Done {
+		return false, nil
+	}
+	if op.Error != nil {
+		return true, &OperationError{Name: name, Code: op.Error.Code, Message: op.Error.Message}
+	}
+	return true, nil
+}
+
+// OperationError is returned by a Getter when a polled Operation terminated
+// unsuccessfully. Code is the gRPC canonical status code reported on the
+// Operation's Error field (https://pkg.go.dev/google.golang.org/grpc/codes),
+// not an HTTP status, since that is what the long-running-operations API
+// actually returns.
+type OperationError struct {
+	Name    string
+	Code    int64
+	Message string
+}
+
+// Error implements error.
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %s failed: %s (code %d)", e.Name, e.Message, e.Code)
+}
+
+// gRPC canonical status codes relevant to Classify, mirrored here as plain
+// constants rather than pulling in google.golang.org/grpc/codes for three
+// integers. See https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto.
+const (
+	grpcCodeAlreadyExists     = 6
+	grpcCodePermissionDenied  = 7
+	grpcCodeResourceExhausted = 8
+)
+
+// OperationWaiter polls a single long-running GCP operation through a Getter
+// and reports whether it has finished, without ever blocking the caller.
+type OperationWaiter struct {
+	getter Getter
+}
+
+// NewOperationWaiter returns an OperationWaiter that polls operations through getter.
+func NewOperationWaiter(getter Getter) *OperationWaiter {
+	return &OperationWaiter{getter: getter}
+}
+
+// Waited performs a single, non-blocking check of the named operation and
+// reports whether it is done. An empty name is treated as already done, since
+// some calls (e.g. a CreateProject that raced with an existing project)
+// resolve synchronously and never produce an operation to poll. Callers in a
+// reconcile loop should requeue with Backoff(attempt) when done is false and
+// err is nil, rather than looping here.
+func (w *OperationWaiter) Waited(ctx context.Context, name string) (done bool, err error) {
+	if name == "" {
+		return true, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	return w.getter.Get(name)
+}
+
+// Backoff returns an exponential backoff duration for the given attempt
+// (attempts start at 0), capped at 60s and jittered by up to 20% so that many
+// ClusterDeployments resuming the same kind of operation do not all requeue
+// in lockstep.
+func Backoff(attempt int) time.Duration {
+	backoff := time.Second * time.Duration(uint64(1)<<uint(attempt))
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(backoff) / 5))
+	return backoff + jitter
+}
+
+// ErrorClass distinguishes why a long-running Operation terminated
+// unsuccessfully, so a caller can decide whether resuming from where it left
+// off is safe or whether the whole reconcile needs to start over.
+type ErrorClass string
+
+const (
+	// ErrorClassConflict means the resource already exists (e.g. the project
+	// name is taken) and it is usually safe to treat the step as done.
+	ErrorClassConflict ErrorClass = "Conflict"
+	// ErrorClassQuota means the operation was rejected due to a quota or rate
+	// limit and is worth retrying later.
+	ErrorClassQuota ErrorClass = "QuotaExceeded"
+	// ErrorClassPermission means the operator's credentials lack a required
+	// permission and retrying without operator intervention will not help.
+	ErrorClassPermission ErrorClass = "PermissionDenied"
+	// ErrorClassUnknown covers any other terminal error.
+	ErrorClassUnknown ErrorClass = "Unknown"
+)
+
+// Classify inspects err and reports which terminal class it falls into. err
+// may be a *googleapi.Error, as returned by a direct (synchronous) API call,
+// or an *OperationError, as returned by a Getter polling an asynchronous one
+// to completion — the two carry different code spaces (HTTP vs. gRPC
+// canonical), so each is mapped separately.
+func Classify(err error) ErrorClass {
+	if ae, ok := err.(*googleapi.Error); ok {
+		switch ae.Code {
+		case http.StatusConflict:
+			return ErrorClassConflict
+		case http.StatusForbidden:
+			return ErrorClassPermission
+		case http.StatusTooManyRequests:
+			return ErrorClassQuota
+		}
+	}
+	if oe, ok := err.(*OperationError); ok {
+		switch oe.Code {
+		case grpcCodeAlreadyExists:
+			return ErrorClassConflict
+		case grpcCodePermissionDenied:
+			return ErrorClassPermission
+		case grpcCodeResourceExhausted:
+			return ErrorClassQuota
+		}
+	}
+	return ErrorClassUnknown
+}
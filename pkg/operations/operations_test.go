@@ -0,0 +1,91 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+type fakeGetter struct {
+	done bool
+	err  error
+}
+
+func (f *fakeGetter) Get(name string) (bool, error) {
+	return f.done, f.err
+}
+
+func TestWaiterWaited(t *testing.T) {
+	tests := []struct {
+		name     string
+		opName   string
+		getter   *fakeGetter
+		wantDone bool
+		wantErr  bool
+	}{
+		{name: "empty operation name is already done", opName: "", getter: &fakeGetter{}, wantDone: true},
+		{name: "still running", opName: "operations/123", getter: &fakeGetter{done: false}, wantDone: false},
+		{name: "done", opName: "operations/123", getter: &fakeGetter{done: true}, wantDone: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			waiter := NewOperationWaiter(test.getter)
+			done, err := waiter.Waited(context.Background(), test.opName)
+			if done != test.wantDone {
+				t.Errorf("Waited() done = %v, want %v", done, test.wantDone)
+			}
+			if (err != nil) != test.wantErr {
+				t.Errorf("Waited() err = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackoffCapped(t *testing.T) {
+	for attempt := 0; attempt < 20; attempt++ {
+		backoff := Backoff(attempt)
+		if backoff > maxBackoff+maxBackoff/5 {
+			t.Errorf("Backoff(%d) = %v, want <= %v", attempt, backoff, maxBackoff+maxBackoff/5)
+		}
+		if backoff <= 0 {
+			t.Errorf("Backoff(%d) = %v, want > 0", attempt, backoff)
+		}
+	}
+}
+
+func TestBackoffGrows(t *testing.T) {
+	if Backoff(3) < time.Second {
+		t.Errorf("Backoff(3) = %v, expected it to grow past the first attempt", Backoff(3))
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{name: "googleapi conflict", err: &googleapi.Error{Code: http.StatusConflict}, want: ErrorClassConflict},
+		{name: "googleapi forbidden", err: &googleapi.Error{Code: http.StatusForbidden}, want: ErrorClassPermission},
+		{name: "googleapi too many requests", err: &googleapi.Error{Code: http.StatusTooManyRequests}, want: ErrorClassQuota},
+		{name: "googleapi other", err: &googleapi.Error{Code: http.StatusInternalServerError}, want: ErrorClassUnknown},
+		{name: "operation already exists", err: &OperationError{Code: grpcCodeAlreadyExists}, want: ErrorClassConflict},
+		{name: "operation permission denied", err: &OperationError{Code: grpcCodePermissionDenied}, want: ErrorClassPermission},
+		{name: "operation resource exhausted", err: &OperationError{Code: grpcCodeResourceExhausted}, want: ErrorClassQuota},
+		{name: "operation other", err: &OperationError{Code: 13}, want: ErrorClassUnknown},
+		{name: "plain error", err: fmt.Errorf("boom"), want: ErrorClassUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Classify(test.err); got != test.want {
+				t.Errorf("Classify() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/pkg/apis/gcp/v1alpha1"
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"golang.org/x/oauth2/google"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OperatorNamespace is the namespace the operator and its singleton
+	// config/credential objects run in.
+	OperatorNamespace = "gcp-project-operator"
+	// operatorConfigName is the well-known name of the singleton
+	// GCPProjectOperatorConfig the operator reads its credential source and
+	// required-services/billing overrides from.
+	operatorConfigName = "gcp-project-operator"
+	// orgGcpSecretName is the org-wide GCP credentials Secret, in
+	// OperatorNamespace, used as the JSONKey credential source and as the
+	// fallback source of the org billing account.
+	orgGcpSecretName = "gcp-project-operator"
+
+	// workloadIdentityConfigPath is where a projected ServiceAccountToken
+	// volume mounts the external_account credential configuration consumed
+	// by gcpclient.WorkloadIdentityFederationSource, when configured.
+	workloadIdentityConfigPath = "/var/run/secrets/gcp-project-operator/credential-configuration.json"
+
+	// orgGcpSecretJSONKey is the key, in orgGcpSecretName, holding the
+	// service account JSON used as the JSONKey credential source.
+	orgGcpSecretJSONKey = "osServiceAccount.json"
+	// orgGcpSecretBillingAccountKey is the key, in orgGcpSecretName, holding
+	// the org billing account ID projects are linked to by default.
+	orgGcpSecretBillingAccountKey = "billingaccount"
+)
+
+// ResolveGCPCredentials builds the google.Credentials the operator should use
+// to talk to GCP, honoring the CredentialSource chosen in the
+// GCPProjectOperatorConfig CR (falling back through the org secret, GCE/GKE
+// metadata, then workload identity federation when no CR is present).
+func ResolveGCPCredentials(ctx context.Context, c client.Client) (*google.Credentials, error) {
+	orgAuthJSON, err := getGCPCredentialsFromSecret(c)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &gcpv1alpha1.GCPProjectOperatorConfig{}
+	err = c.Get(ctx, types.NamespacedName{Name: operatorConfigName, Namespace: OperatorNamespace}, cfg)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("could not get GCPProjectOperatorConfig %s/%s: %v", OperatorNamespace, operatorConfigName, err)
+		}
+		cfg = nil
+	}
+
+	// The federation config is optional, so a missing file just means that
+	// source is not available rather than an error.
+	federationJSON, _ := ioutil.ReadFile(workloadIdentityConfigPath)
+
+	source := gcpclient.ResolveCredentialSource(cfg, orgAuthJSON, federationJSON)
+	creds, err := source.Credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve gcp credentials: %v", err)
+	}
+
+	return creds, nil
+}
+
+// ResolveRequiredServices returns the GCP APIs that must be enabled on every
+// provisioned project, honoring a RequiredServices override on the
+// GCPProjectOperatorConfig CR and falling back to
+// gcpclient.DefaultRequiredServices when no CR is present or it doesn't set
+// one.
+func ResolveRequiredServices(ctx context.Context, c client.Client) ([]string, error) {
+	cfg := &gcpv1alpha1.GCPProjectOperatorConfig{}
+	err := c.Get(ctx, types.NamespacedName{Name: operatorConfigName, Namespace: OperatorNamespace}, cfg)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("could not get GCPProjectOperatorConfig %s/%s: %v", OperatorNamespace, operatorConfigName, err)
+		}
+		return gcpclient.DefaultRequiredServices, nil
+	}
+
+	if len(cfg.Spec.RequiredServices) > 0 {
+		return cfg.Spec.RequiredServices, nil
+	}
+
+	return gcpclient.DefaultRequiredServices, nil
+}
+
+// ResolveKeyRotationPeriod returns how long a provisioned project's service
+// account key should be kept before being rotated, honoring a
+// KeyRotationPeriod override on the GCPProjectOperatorConfig CR and falling
+// back to defaultPeriod when no CR is present or it doesn't set one.
+func ResolveKeyRotationPeriod(ctx context.Context, c client.Client, defaultPeriod time.Duration) (time.Duration, error) {
+	cfg := &gcpv1alpha1.GCPProjectOperatorConfig{}
+	err := c.Get(ctx, types.NamespacedName{Name: operatorConfigName, Namespace: OperatorNamespace}, cfg)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return 0, fmt.Errorf("could not get GCPProjectOperatorConfig %s/%s: %v", OperatorNamespace, operatorConfigName, err)
+		}
+		return defaultPeriod, nil
+	}
+
+	if cfg.Spec.KeyRotationPeriod != nil {
+		return cfg.Spec.KeyRotationPeriod.Duration, nil
+	}
+
+	return defaultPeriod, nil
+}
+
+// ResolveBillingAccount returns override if set, otherwise the org billing
+// account recorded in orgGcpSecretName.
+func ResolveBillingAccount(c client.Client, override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	return getBillingAccountFromSecret(c)
+}
+
+// getGCPCredentialsFromSecret reads the service account JSON held at
+// orgGcpSecretJSONKey in the org-wide orgGcpSecretName secret. A missing
+// secret is not an error: it returns nil, so the credential chain falls
+// through to its metadata and federation sources.
+func getGCPCredentialsFromSecret(c client.Client) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: orgGcpSecretName, Namespace: OperatorNamespace}, secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not get secret %s/%s: %v", OperatorNamespace, orgGcpSecretName, err)
+	}
+
+	data, ok := secret.Data[orgGcpSecretJSONKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s is missing key %s", OperatorNamespace, orgGcpSecretName, orgGcpSecretJSONKey)
+	}
+
+	return data, nil
+}
+
+// getBillingAccountFromSecret reads the org billing account ID held at
+// orgGcpSecretBillingAccountKey in the org-wide orgGcpSecretName secret.
+func getBillingAccountFromSecret(c client.Client) (string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: orgGcpSecretName, Namespace: OperatorNamespace}, secret); err != nil {
+		return "", fmt.Errorf("could not get secret %s/%s: %v", OperatorNamespace, orgGcpSecretName, err)
+	}
+
+	data, ok := secret.Data[orgGcpSecretBillingAccountKey]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s is missing key %s", OperatorNamespace, orgGcpSecretName, orgGcpSecretBillingAccountKey)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,162 @@
+// Package project implements the GCP project provisioning pipeline run by
+// the projectclaim controller (the clusterdeployment controller reaches it
+// only through the ProjectClaim it creates): CreateProject ->
+// EnableServices -> BindingIAM -> CreateKey. It knows nothing about
+// ClusterDeployment, ProjectClaim, or ProjectReference; callers persist
+// whatever bookkeeping (pending-operation annotations, conditions) they need
+// onto their own objects and call back in on the next reconcile.
+package project
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/openshift/gcp-project-operator/pkg/iampolicy"
+	"github.com/openshift/gcp-project-operator/pkg/operations"
+	"google.golang.org/api/iam/v1"
+)
+
+const (
+	// PendingOperationAnnotation stores the name of an in-flight GCP
+	// long-running operation so a later reconcile can resume waiting on it
+	// instead of starting the step over after a controller restart.
+	PendingOperationAnnotation = "gcp.openshift.io/pending-operation"
+	// PendingOperationAttemptsAnnotation counts how many times a reconcile
+	// has requeued waiting on PendingOperationAnnotation, to drive backoff.
+	PendingOperationAttemptsAnnotation = "gcp.openshift.io/pending-operation-attempts"
+	// PendingOperationSourceAnnotation records which GCP API issued the
+	// operation named in PendingOperationAnnotation, so it can be polled
+	// through the matching Check*Operation method via CheckPending.
+	PendingOperationSourceAnnotation = "gcp.openshift.io/pending-operation-source"
+
+	// PendingOperationSourceCloudResourceManager marks a pending operation as
+	// coming from a cloudresourcemanager call (e.g. CreateProject).
+	PendingOperationSourceCloudResourceManager = "cloudresourcemanager"
+	// PendingOperationSourceServiceUsage marks a pending operation as coming
+	// from a serviceusage call (e.g. EnableServices).
+	PendingOperationSourceServiceUsage = "serviceusage"
+)
+
+// Request describes the GCP project Ensure should provision or re-verify.
+type Request struct {
+	ProjectID          string
+	ParentFolderID     string
+	BillingAccount     string
+	RequiredServices   []string
+	RequiredRoles      []string
+	ServiceAccountName string
+	// RotatingKey is true when an existing secret's key is being rotated
+	// rather than minted for the first time, so Ensure lists the keys it is
+	// about to supersede instead of defensively deleting every stray key.
+	RotatingKey bool
+}
+
+// Result is what Ensure produced once the pipeline completed without hitting
+// a pending operation.
+type Result struct {
+	ServiceAccount *iam.ServiceAccount
+	// PreviousKeys are the USER_MANAGED keys that existed on ServiceAccount
+	// before the key in PrivateKeyData was minted. Only populated when
+	// Request.RotatingKey is true; callers defer deleting them until
+	// consumers have had a chance to pick up the new key.
+	PreviousKeys []*iam.ServiceAccountKey
+	// PrivateKeyData is the decoded (not base64) service account key JSON.
+	PrivateKeyData string
+}
+
+// PendingOperation is returned by Ensure when a GCP long-running operation is
+// still in flight. Callers persist Name/Source onto their own object under
+// PendingOperationAnnotation/PendingOperationSourceAnnotation, requeue, and
+// resume via CheckPending on the next reconcile.
+type PendingOperation struct {
+	Name   string
+	Source string
+}
+
+// Ensure runs CreateProject -> EnableServices -> CreateCloudBillingAccount ->
+// BindingIAM -> CreateServiceAccountKey against gClient, which must already
+// be scoped to req.ProjectID. The projectclaim controller is its caller.
+func Ensure(gClient gcpclient.Client, req Request) (*Result, *PendingOperation, error) {
+	operation, err := gClient.CreateProject(req.ParentFolderID)
+	if err != nil {
+		// A resumed reconcile re-enters here after a previous CreateProject's
+		// async operation already finished, so the project now exists and a
+		// second Create legitimately 409s. Treat that as done rather than
+		// failing the whole pipeline.
+		if operations.Classify(err) != operations.ErrorClassConflict {
+			return nil, nil, fmt.Errorf("could not create project: %v", err)
+		}
+		operation = nil
+	}
+	if operation != nil && operation.Name != "" && !operation.Done {
+		return nil, &PendingOperation{Name: operation.Name, Source: PendingOperationSourceCloudResourceManager}, nil
+	}
+
+	enableOperation, err := gClient.EnableServices(req.ProjectID, req.RequiredServices)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not enable required services: %v", err)
+	}
+	if enableOperation != nil && enableOperation.Name != "" && !enableOperation.Done {
+		return nil, &PendingOperation{Name: enableOperation.Name, Source: PendingOperationSourceServiceUsage}, nil
+	}
+
+	// TODO(MJ): Perm issue in the api
+	// https://groups.google.com/forum/#!topic/gce-discussion/K_x9E0VIckk
+	if err := gClient.CreateCloudBillingAccount(req.ProjectID, req.BillingAccount); err != nil {
+		return nil, nil, fmt.Errorf("could not link billing account: %v", err)
+	}
+
+	serviceAccount, err := gClient.GetServiceAccount(req.ServiceAccountName)
+	if err != nil {
+		account, err := gClient.CreateServiceAccount(req.ServiceAccountName, req.ServiceAccountName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create service account: %v", err)
+		}
+		serviceAccount = account
+	}
+
+	member := fmt.Sprintf("serviceAccount:%s", serviceAccount.Email)
+	desiredBindings := make([]iampolicy.Binding, len(req.RequiredRoles))
+	for i, role := range req.RequiredRoles {
+		desiredBindings[i] = iampolicy.Binding{Role: role, Members: []string{member}}
+	}
+	if _, err := iampolicy.Apply(gClient, req.ProjectID, desiredBindings); err != nil {
+		return nil, nil, fmt.Errorf("could not reconcile iam policy on project: %v", err)
+	}
+
+	var previousKeys []*iam.ServiceAccountKey
+	if req.RotatingKey {
+		previousKeys, err = gClient.ListServiceAccountKeys(serviceAccount.Email)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not list service account keys: %v", err)
+		}
+	} else if err := gClient.DeleteServiceAccountKeys(serviceAccount.Email); err != nil {
+		// First-time creation: defensively clear out any stray keys left by a
+		// previous partial run before minting the one the caller will store.
+		return nil, nil, fmt.Errorf("could not clear stray service account keys: %v", err)
+	}
+
+	key, err := gClient.CreateServiceAccountKey(serviceAccount.Email)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create service account key: %v", err)
+	}
+
+	privateKeyData, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not decode service account key: %v", err)
+	}
+
+	return &Result{ServiceAccount: serviceAccount, PreviousKeys: previousKeys, PrivateKeyData: string(privateKeyData)}, nil, nil
+}
+
+// CheckPending polls the GCP operation named in pending through the API it
+// came from, reporting whether it has finished.
+func CheckPending(gClient gcpclient.Client, pending PendingOperation) (bool, error) {
+	switch pending.Source {
+	case PendingOperationSourceServiceUsage:
+		return gClient.CheckServiceUsageOperation(pending.Name)
+	default:
+		return gClient.CheckCloudResourceManagerOperation(pending.Name)
+	}
+}
@@ -0,0 +1,56 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretKey is the key, in a provisioned project's credential Secret,
+// holding the service account's private key JSON.
+const SecretKey = "osServiceAccountJson"
+
+// NewSecretCR builds the Secret a provisioned project's service account key
+// is stored in, named name in namespace.
+func NewSecretCR(name, namespace, privateKeyData string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		StringData: map[string]string{
+			SecretKey: privateKeyData,
+		},
+	}
+}
+
+// GetSecret returns the named Secret, or nil if it does not exist.
+func GetSecret(c client.Client, name, namespace string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// DeleteSecretIfExists removes the named secret, tolerating it already being gone.
+func DeleteSecretIfExists(c client.Client, name, namespace string) error {
+	secret, err := GetSecret(c, name, namespace)
+	if err != nil {
+		return fmt.Errorf("could not get secret %s/%s: %v", namespace, name, err)
+	}
+	if secret == nil {
+		return nil
+	}
+
+	return c.Delete(context.Background(), secret)
+}
@@ -0,0 +1,90 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// TeardownRequest describes the GCP project, service account, and optional
+// shared-VPC (XPN) host project a release should clean up.
+type TeardownRequest struct {
+	ProjectID           string
+	ParentFolderID      string
+	ServiceAccountEmail string
+	// XPNHostProjectID, when non-empty, also has RemoveMemberFromProjectPolicy
+	// run against it via hostClient.
+	XPNHostProjectID string
+}
+
+// Teardown deletes the service account keys and service account
+// ServiceAccountEmail names, strips it from ProjectID's IAM policy (and
+// XPNHostProjectID's, via hostClient, if set), then deletes ProjectID
+// itself. gClient must be scoped to ProjectID; hostClient, if req has an XPN
+// host project, must be scoped to it. Errors deleting the service account or
+// its keys are logged by the caller rather than failing the release, since a
+// project delete reclaims them anyway.
+func Teardown(gClient, hostClient gcpclient.Client, req TeardownRequest) (saErrs []error, err error) {
+	if delErr := gClient.DeleteServiceAccountKeys(req.ServiceAccountEmail); delErr != nil {
+		saErrs = append(saErrs, fmt.Errorf("could not delete service account keys: %v", delErr))
+	}
+
+	if delErr := gClient.DeleteServiceAccount(req.ServiceAccountEmail); delErr != nil {
+		saErrs = append(saErrs, fmt.Errorf("could not delete service account: %v", delErr))
+	}
+
+	if err := RemoveMemberFromProjectPolicy(gClient, req.ProjectID, req.ServiceAccountEmail); err != nil {
+		return saErrs, fmt.Errorf("could not clean up iam policy on project %s: %v", req.ProjectID, err)
+	}
+
+	if req.XPNHostProjectID != "" {
+		if hostClient == nil {
+			return saErrs, fmt.Errorf("xpn host project %s configured but no client provided", req.XPNHostProjectID)
+		}
+		if err := RemoveMemberFromProjectPolicy(hostClient, req.XPNHostProjectID, req.ServiceAccountEmail); err != nil {
+			return saErrs, fmt.Errorf("could not clean up iam policy on xpn host project %s: %v", req.XPNHostProjectID, err)
+		}
+	}
+
+	if _, err := gClient.DeleteProject(req.ParentFolderID); err != nil {
+		return saErrs, fmt.Errorf("could not delete project %s: %v", req.ProjectID, err)
+	}
+
+	return saErrs, nil
+}
+
+// RemoveMemberFromProjectPolicy strips the member "serviceAccount:<serviceAccountEmail>"
+// from every binding in projectID's policy, leaving all other members untouched.
+func RemoveMemberFromProjectPolicy(gClient gcpclient.Client, projectID, serviceAccountEmail string) error {
+	member := fmt.Sprintf("serviceAccount:%s", serviceAccountEmail)
+
+	policy, err := gClient.GetIamPolicy(projectID)
+	if err != nil {
+		return fmt.Errorf("could not get iam policy: %v", err)
+	}
+
+	changed := false
+	for _, binding := range policy.Bindings {
+		members := binding.Members[:0]
+		for _, m := range binding.Members {
+			if m == member {
+				changed = true
+				continue
+			}
+			members = append(members, m)
+		}
+		binding.Members = members
+	}
+
+	if !changed {
+		return nil
+	}
+
+	_, err = gClient.SetIamPolicy(&cloudresourcemanager.SetIamPolicyRequest{Policy: policy})
+	if err != nil {
+		return fmt.Errorf("could not set iam policy: %v", err)
+	}
+
+	return nil
+}
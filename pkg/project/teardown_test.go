@@ -0,0 +1,186 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient/mock"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+const testServiceAccountEmail = "osd-managed-admin@o-123abc.iam.gserviceaccount.com"
+
+func policyWithMember(member string) *cloudresourcemanager.Policy {
+	return &cloudresourcemanager.Policy{
+		Bindings: []*cloudresourcemanager.Binding{
+			{
+				Role:    "roles/storage.admin",
+				Members: []string{member, "user:someone@example.com"},
+			},
+			{
+				Role:    "roles/dns.admin",
+				Members: []string{member},
+			},
+		},
+	}
+}
+
+func TestRemoveMemberFromProjectPolicy(t *testing.T) {
+	member := "serviceAccount:" + testServiceAccountEmail
+
+	tests := []struct {
+		name      string
+		policy    *cloudresourcemanager.Policy
+		setCalled bool
+	}{
+		{
+			name:      "service-scoped project policy still has the binding",
+			policy:    policyWithMember(member),
+			setCalled: true,
+		},
+		{
+			name: "host-scoped project policy still has the binding",
+			policy: &cloudresourcemanager.Policy{
+				Bindings: []*cloudresourcemanager.Binding{
+					{
+						Role:    "roles/compute.networkUser",
+						Members: []string{member, "serviceAccount:other@o-123abc.iam.gserviceaccount.com"},
+					},
+				},
+			},
+			setCalled: true,
+		},
+		{
+			name: "member already absent is a no-op",
+			policy: &cloudresourcemanager.Policy{
+				Bindings: []*cloudresourcemanager.Binding{
+					{Role: "roles/storage.admin", Members: []string{"user:someone@example.com"}},
+				},
+			},
+			setCalled: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock.NewMockClient(ctrl)
+			mockClient.EXPECT().GetIamPolicy("o-123abc").Return(test.policy, nil)
+
+			if test.setCalled {
+				mockClient.EXPECT().SetIamPolicy(gomock.Any()).DoAndReturn(
+					func(req *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+						for _, binding := range req.Policy.Bindings {
+							for _, m := range binding.Members {
+								if m == member {
+									t.Errorf("expected %s to be removed from binding %s, but it is still present", member, binding.Role)
+								}
+							}
+						}
+						return req.Policy, nil
+					})
+			}
+
+			if err := RemoveMemberFromProjectPolicy(mockClient, "o-123abc", testServiceAccountEmail); err != nil {
+				t.Fatalf("RemoveMemberFromProjectPolicy() returned unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTeardown(t *testing.T) {
+	member := "serviceAccount:" + testServiceAccountEmail
+
+	t.Run("cleans up both project-scoped and host-scoped policies", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gClient := mock.NewMockClient(ctrl)
+		gClient.EXPECT().DeleteServiceAccountKeys(testServiceAccountEmail).Return(nil)
+		gClient.EXPECT().DeleteServiceAccount(testServiceAccountEmail).Return(nil)
+		gClient.EXPECT().GetIamPolicy("o-123abc").Return(policyWithMember(member), nil)
+		gClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+		gClient.EXPECT().DeleteProject("folder-1").Return(nil, nil)
+
+		hostClient := mock.NewMockClient(ctrl)
+		hostClient.EXPECT().GetIamPolicy("host-project").Return(&cloudresourcemanager.Policy{
+			Bindings: []*cloudresourcemanager.Binding{
+				{Role: "roles/compute.networkUser", Members: []string{member}},
+			},
+		}, nil)
+		hostClient.EXPECT().SetIamPolicy(gomock.Any()).DoAndReturn(
+			func(req *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+				for _, binding := range req.Policy.Bindings {
+					for _, m := range binding.Members {
+						if m == member {
+							t.Errorf("expected %s to be removed from host project binding %s, but it is still present", member, binding.Role)
+						}
+					}
+				}
+				return req.Policy, nil
+			})
+
+		req := TeardownRequest{
+			ProjectID:           "o-123abc",
+			ParentFolderID:      "folder-1",
+			ServiceAccountEmail: testServiceAccountEmail,
+			XPNHostProjectID:    "host-project",
+		}
+
+		if _, err := Teardown(gClient, hostClient, req); err != nil {
+			t.Fatalf("Teardown() returned unexpected error: %v", err)
+		}
+	})
+
+	t.Run("xpn host project configured but no host client provided", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gClient := mock.NewMockClient(ctrl)
+		gClient.EXPECT().DeleteServiceAccountKeys(testServiceAccountEmail).Return(nil)
+		gClient.EXPECT().DeleteServiceAccount(testServiceAccountEmail).Return(nil)
+		gClient.EXPECT().GetIamPolicy("o-123abc").Return(policyWithMember(member), nil)
+		gClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+
+		req := TeardownRequest{
+			ProjectID:           "o-123abc",
+			ParentFolderID:      "folder-1",
+			ServiceAccountEmail: testServiceAccountEmail,
+			XPNHostProjectID:    "host-project",
+		}
+
+		if _, err := Teardown(gClient, nil, req); err == nil {
+			t.Fatal("Teardown() expected an error when XPNHostProjectID is set but hostClient is nil, got nil")
+		}
+	})
+}
+
+func TestRemoveMemberFromProjectPolicyPreservesForeignMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	member := "serviceAccount:" + testServiceAccountEmail
+	foreign := "user:someone@example.com"
+
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.EXPECT().GetIamPolicy("o-123abc").Return(policyWithMember(member), nil)
+	mockClient.EXPECT().SetIamPolicy(gomock.Any()).DoAndReturn(
+		func(req *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+			found := false
+			for _, m := range req.Policy.Bindings[0].Members {
+				if m == foreign {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected foreign member %s to be preserved", foreign)
+			}
+			return req.Policy, nil
+		})
+
+	if err := RemoveMemberFromProjectPolicy(mockClient, "o-123abc", testServiceAccountEmail); err != nil {
+		t.Fatalf("RemoveMemberFromProjectPolicy() returned unexpected error: %v", err)
+	}
+}